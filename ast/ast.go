@@ -11,7 +11,29 @@
 
 package ast
 
-type Node interface{}
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pos is where a node starts in its source.
+type Pos struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Pos) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+type Node interface {
+	Position() Pos
+}
 
 type Statement interface {
 	Node
@@ -24,14 +46,51 @@ type Expression interface {
 }
 
 type Program struct {
+	Pos        Pos
 	Statements []Statement
 }
 
 type Type struct {
-	Value string
+	Pos        Pos
+	Value      string
+	Return     *Type
+	Parameters []Param
+	Elements   []Type
+}
+
+// FuncType builds the type of a function, e.g. "int(int,intarr)", from its
+// return type and parameters.
+func FuncType(ret Type, params []Param) Type {
+	types := make([]string, len(params))
+	for i, param := range params {
+		types[i] = param.Type.Value
+		if param.Array {
+			types[i] += "arr"
+		}
+	}
+	return Type{
+		Pos:        ret.Pos,
+		Value:      ret.Value + "(" + strings.Join(types, ",") + ")",
+		Return:     &ret,
+		Parameters: params,
+	}
+}
+
+// TupleType builds the type of a list of values returned together, e.g.
+// "(int,int)".
+func TupleType(elements []Type) Type {
+	types := make([]string, len(elements))
+	for i, element := range elements {
+		types[i] = element.Value
+	}
+	return Type{
+		Value:    "(" + strings.Join(types, ",") + ")",
+		Elements: elements,
+	}
 }
 
 type FuncDecl struct {
+	Pos        Pos
 	Type       Type
 	Ident      Identifier
 	Parameters []Param
@@ -39,10 +98,31 @@ type FuncDecl struct {
 }
 
 type VarDecl struct {
+	Pos         Pos
 	Type        Type
 	Ident       Identifier
 	Value       Expression
+	Dimensions  []Expression
 	Initialized bool
+	Const       bool
+}
+
+type TupleDecl struct {
+	Pos   Pos
+	Decls []VarDecl
+	Value Expression
+}
+
+type StructDecl struct {
+	Pos    Pos
+	Ident  Identifier
+	Fields []VarDecl
+}
+
+type EnumDecl struct {
+	Pos     Pos
+	Ident   Identifier
+	Members []Identifier
 }
 
 type Param struct {
@@ -52,15 +132,26 @@ type Param struct {
 }
 
 type Block struct {
+	Pos        Pos
 	Statements []Statement
 }
 
 type While struct {
+	Pos       Pos
 	Condition Expression
 	Body      Statement
+	Label     Identifier
+}
+
+type DoWhile struct {
+	Pos       Pos
+	Body      Statement
+	Condition Expression
+	Label     Identifier
 }
 
 type For struct {
+	Pos       Pos
 	Init      Expression
 	Condition Expression
 	Increment Expression
@@ -69,118 +160,380 @@ type For struct {
 	Type      Type
 	Ident     Identifier
 	Value     Expression
+	Label     Identifier
+}
+
+type ForEach struct {
+	Pos        Pos
+	Type       Type
+	Ident      Identifier
+	Collection Expression
+	Body       Statement
+	Label      Identifier
 }
 
 type IfElse struct {
+	Pos            Pos
 	Condition      Expression
 	Consequence    Statement
 	Alternative    Statement
 	HasAlternative bool
 }
 
+type Switch struct {
+	Pos   Pos
+	Value Expression
+	Cases []Case
+}
+
+type Case struct {
+	Pos         Pos
+	Values      []Expression
+	Body        Block
+	Default     bool
+	Fallthrough bool
+}
+
 type Return struct {
+	Pos   Pos
 	Value Expression
 	Void  bool
 }
 
+type Break struct {
+	Pos   Pos
+	Label Identifier
+}
+
+type Continue struct {
+	Pos   Pos
+	Label Identifier
+}
+
 type ExprStmt struct {
+	Pos        Pos
 	Expression Expression
 }
 
 type PrefixExpr struct {
+	Pos   Pos
 	Op    string
 	Right Expression
 }
 
 type InfixExpr struct {
+	Pos   Pos
 	Left  Expression
 	Op    string
 	Right Expression
 }
 
+type Ternary struct {
+	Pos         Pos
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+type Cast struct {
+	Pos   Pos
+	Type  Type
+	Value Expression
+}
+
 type Assign struct {
+	Pos   Pos
 	Ident Identifier
 	Value Expression
 }
 
 type AssignExpr struct {
+	Pos   Pos
 	Ident Identifier
 	Op    string
 	Value Expression
 }
 
+type IncDecExpr struct {
+	Pos    Pos
+	Ident  Identifier
+	Op     string
+	Prefix bool
+}
+
+type Tuple struct {
+	Pos      Pos
+	Elements []Expression
+}
+
 type Call struct {
+	Pos       Pos
 	Function  Identifier
 	Arguments []Expression
 	Void      bool
 }
 
+type Lambda struct {
+	Pos        Pos
+	Type       Type
+	Parameters []Param
+	Body       Block
+}
+
 type Identifier struct {
+	Pos  Pos
 	Name string
 }
 
 type CharCon struct {
-	Value string
+	Pos   Pos
+	Value rune
 }
 
 type IntCon struct {
+	Pos   Pos
 	Value int64
 }
 
+type BigIntCon struct {
+	Pos   Pos
+	Value string
+}
+
 type FloatCon struct {
+	Pos   Pos
 	Value float64
 }
 
 type StringCon struct {
+	Pos   Pos
 	Value string
 }
 
 type Bool struct {
+	Pos   Pos
 	Value bool
 }
 
 type Array struct {
+	Pos      Pos
 	Elements []Expression
 }
 
 type IndexExpr struct {
-	Ident Identifier
+	Pos   Pos
+	Left  Expression
 	Index Expression
 }
 
+type SliceExpr struct {
+	Pos  Pos
+	Left Expression
+	Low  Expression
+	High Expression
+}
+
 type AssignIndexExpr struct {
-	Ident Identifier
+	Pos   Pos
+	Left  Expression
 	Index Expression
 	Value Expression
 }
 
 type AssignExprIndexExpr struct {
-	Ident Identifier
+	Pos   Pos
+	Left  Expression
 	Index Expression
 	Op    string
 	Value Expression
 }
 
+type IncDecIndexExpr struct {
+	Pos    Pos
+	Left   Expression
+	Index  Expression
+	Op     string
+	Prefix bool
+}
+
+type FieldExpr struct {
+	Pos   Pos
+	Left  Expression
+	Field Identifier
+}
+
+type AssignExprFieldExpr struct {
+	Pos   Pos
+	Left  Expression
+	Field Identifier
+	Op    string
+	Value Expression
+}
+
+type IncDecFieldExpr struct {
+	Pos    Pos
+	Left   Expression
+	Field  Identifier
+	Op     string
+	Prefix bool
+}
+
 func (fd FuncDecl) statement()               {}
 func (vd VarDecl) statement()                {}
+func (td TupleDecl) statement()              {}
+func (sd StructDecl) statement()             {}
+func (ed EnumDecl) statement()               {}
 func (bs Block) statement()                  {}
 func (w While) statement()                   {}
+func (dw DoWhile) statement()                {}
 func (f For) statement()                     {}
+func (fe ForEach) statement()                {}
 func (is IfElse) statement()                 {}
+func (sw Switch) statement()                 {}
 func (r Return) statement()                  {}
+func (b Break) statement()                   {}
+func (c Continue) statement()                {}
 func (es ExprStmt) statement()               {}
 func (pe PrefixExpr) expression()            {}
 func (ie InfixExpr) expression()             {}
+func (t Ternary) expression()                {}
+func (c Cast) expression()                   {}
 func (a Assign) expression()                 {}
 func (ae AssignExpr) expression()            {}
+func (ide IncDecExpr) expression()           {}
+func (t Tuple) expression()                  {}
 func (ce Call) expression()                  {}
+func (l Lambda) expression()                 {}
 func (i Identifier) expression()             {}
 func (cc CharCon) expression()               {}
 func (ic IntCon) expression()                {}
+func (bc BigIntCon) expression()             {}
 func (fc FloatCon) expression()              {}
 func (sc StringCon) expression()             {}
 func (b Bool) expression()                   {}
 func (a Array) expression()                  {}
 func (ie IndexExpr) expression()             {}
+func (se SliceExpr) expression()             {}
 func (aie AssignIndexExpr) expression()      {}
 func (aeie AssignExprIndexExpr) expression() {}
+func (idie IncDecIndexExpr) expression()     {}
+func (fe FieldExpr) expression()             {}
+func (aefe AssignExprFieldExpr) expression() {}
+func (idfe IncDecFieldExpr) expression()     {}
+
+func (p Program) Position() Pos                { return p.Pos }
+func (fd FuncDecl) Position() Pos              { return fd.Pos }
+func (vd VarDecl) Position() Pos               { return vd.Pos }
+func (td TupleDecl) Position() Pos             { return td.Pos }
+func (sd StructDecl) Position() Pos            { return sd.Pos }
+func (ed EnumDecl) Position() Pos              { return ed.Pos }
+func (bs Block) Position() Pos                 { return bs.Pos }
+func (w While) Position() Pos                  { return w.Pos }
+func (dw DoWhile) Position() Pos               { return dw.Pos }
+func (f For) Position() Pos                    { return f.Pos }
+func (fe ForEach) Position() Pos               { return fe.Pos }
+func (is IfElse) Position() Pos                { return is.Pos }
+func (sw Switch) Position() Pos                { return sw.Pos }
+func (r Return) Position() Pos                 { return r.Pos }
+func (b Break) Position() Pos                  { return b.Pos }
+func (c Continue) Position() Pos               { return c.Pos }
+func (es ExprStmt) Position() Pos              { return es.Pos }
+func (pe PrefixExpr) Position() Pos            { return pe.Pos }
+func (ie InfixExpr) Position() Pos             { return ie.Pos }
+func (t Ternary) Position() Pos                { return t.Pos }
+func (c Cast) Position() Pos                   { return c.Pos }
+func (a Assign) Position() Pos                 { return a.Pos }
+func (ae AssignExpr) Position() Pos            { return ae.Pos }
+func (ide IncDecExpr) Position() Pos           { return ide.Pos }
+func (t Tuple) Position() Pos                  { return t.Pos }
+func (ce Call) Position() Pos                  { return ce.Pos }
+func (l Lambda) Position() Pos                 { return l.Pos }
+func (i Identifier) Position() Pos             { return i.Pos }
+func (cc CharCon) Position() Pos               { return cc.Pos }
+func (ic IntCon) Position() Pos                { return ic.Pos }
+func (bc BigIntCon) Position() Pos             { return bc.Pos }
+func (fc FloatCon) Position() Pos              { return fc.Pos }
+func (sc StringCon) Position() Pos             { return sc.Pos }
+func (b Bool) Position() Pos                   { return b.Pos }
+func (a Array) Position() Pos                  { return a.Pos }
+func (ie IndexExpr) Position() Pos             { return ie.Pos }
+func (se SliceExpr) Position() Pos             { return se.Pos }
+func (aie AssignIndexExpr) Position() Pos      { return aie.Pos }
+func (aeie AssignExprIndexExpr) Position() Pos { return aeie.Pos }
+func (idie IncDecIndexExpr) Position() Pos     { return idie.Pos }
+func (fe FieldExpr) Position() Pos             { return fe.Pos }
+func (aefe AssignExprFieldExpr) Position() Pos { return aefe.Pos }
+func (idfe IncDecFieldExpr) Position() Pos     { return idfe.Pos }
+
+// ExprString renders an expression for use in error messages.
+func ExprString(e Expression) string {
+	switch e := e.(type) {
+	case Identifier:
+		return e.Name
+	case IndexExpr:
+		return ExprString(e.Left) + "[" + ExprString(e.Index) + "]"
+	case SliceExpr:
+		return ExprString(e.Left) + "[:]"
+	case FieldExpr:
+		return ExprString(e.Left) + "." + e.Field.Name
+	case Call:
+		return e.Function.Name + "()"
+	case Tuple:
+		values := make([]string, len(e.Elements))
+		for i, element := range e.Elements {
+			values[i] = ExprString(element)
+		}
+		return strings.Join(values, ", ")
+	case PrefixExpr:
+		return e.Op + ExprString(e.Right)
+	case InfixExpr:
+		return ExprString(e.Left) + " " + e.Op + " " + ExprString(e.Right)
+	case IntCon:
+		return fmt.Sprintf("%d", e.Value)
+	case BigIntCon:
+		return e.Value
+	case CharCon:
+		return strconv.QuoteRune(e.Value)
+	case StringCon:
+		return "\"" + e.Value + "\""
+	default:
+		return "expression"
+	}
+}
+
+// IsIntLiteral reports whether e is an integer literal, or an expression of
+// literals folded into one. Only these take on the fixed-width integer
+// type they are used as; any other int needs a cast.
+func IsIntLiteral(e Expression) bool {
+	_, ok := e.(IntCon)
+	return ok
+}
+
+// ElementOf is the expression for the ith value of an array or tuple literal
+// e, or nil if e is not one.
+func ElementOf(e Expression, i int) Expression {
+	switch e := e.(type) {
+	case Array:
+		if i < len(e.Elements) {
+			return e.Elements[i]
+		}
+	case Tuple:
+		if i < len(e.Elements) {
+			return e.Elements[i]
+		}
+	}
+	return nil
+}
+
+// IsConstant reports whether e is a literal, possibly negated, whose value is
+// known without evaluating it.
+func IsConstant(e Expression) bool {
+	switch e := e.(type) {
+	case CharCon, IntCon, StringCon:
+		return true
+	case PrefixExpr:
+		return IsConstant(e.Right)
+	default:
+		return false
+	}
+}
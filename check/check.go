@@ -0,0 +1,1412 @@
+// Package check type-checks a program before it is evaluated. It resolves
+// every identifier and types every expression by the same rules the
+// evaluator enforces at runtime, so that all of a program's type errors are
+// reported at once instead of one at a time as execution reaches them.
+package check
+
+import (
+	"ariel/ast"
+	"ariel/eval"
+	"ariel/misc"
+	"ariel/object"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// unknown is the type of an expression that could not be typed, either
+// because of an error already reported or because it depends on a value only
+// known at runtime. It is compatible with every other type.
+var unknown = ast.Type{}
+
+var void = ast.Type{Value: "void"}
+
+// operators lists the infix operators each kind of operand supports.
+var operators = map[string][]string{
+	"char":   {"<", "<=", "==", "!=", ">=", ">", "+"},
+	"int":    {"<", "<=", "==", "!=", ">=", ">", "+", "-", "*", "/", "%", "&", "^", "|", "<<", ">>"},
+	"sized":  {"<", "<=", "==", "!=", ">=", ">", "+", "-", "*", "/", "%", "&", "^", "|"},
+	"bigint": {"<", "<=", "==", "!=", ">=", ">", "+", "-", "*", "/", "%", "&", "^", "|"},
+	"enum":   {"<", "<=", "==", "!=", ">=", ">"},
+	"float":  {"<", "<=", "==", "!=", ">=", ">", "+", "-", "*", "/"},
+	"string": {"<", "<=", "==", "!=", ">=", ">", "+"},
+	"bool":   {"==", "!=", "&&", "||"},
+}
+
+type checker struct {
+	errors   []error
+	pos      ast.Pos
+	scope    *scope
+	frame    *frame
+	structs  map[string][]ast.VarDecl
+	enums    map[string][]string
+	deferred []*body
+}
+
+// scope mirrors object.State, mapping names to their declared types,
+// constants to their values where known, and the functions declared in it to
+// their bodies.
+type scope struct {
+	types  map[string]ast.Type
+	consts map[string]bool
+	values map[string]object.Object
+	bodies map[string]*body
+	outer  *scope
+	frame  bool
+}
+
+// body is the pending check of the body of a function or lambda, or of the
+// fields of a struct.
+type body struct {
+	check func()
+	done  bool
+}
+
+// frame tracks the function being checked, for its return statements and the
+// loops and switches that break and continue may refer to.
+type frame struct {
+	name     string
+	ret      ast.Type
+	loops    []string
+	switches int
+}
+
+// Check returns every type error in program.
+func Check(program ast.Program) []error {
+	c := &checker{
+		scope:   newScope(nil, false),
+		frame:   &frame{},
+		structs: make(map[string][]ast.VarDecl),
+		enums:   make(map[string][]string),
+	}
+
+	for _, stmt := range program.Statements {
+		c.stmt(stmt)
+	}
+
+	// Functions that were never called directly are checked against the
+	// names visible at the end of the program.
+	for len(c.deferred) > 0 {
+		next := c.deferred[0]
+		c.deferred = c.deferred[1:]
+		c.checkBody(next)
+	}
+
+	sort.SliceStable(c.errors, func(i, j int) bool {
+		a, b := c.errors[i].(object.Error).Pos, c.errors[j].(object.Error).Pos
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return c.errors
+}
+
+func newScope(outer *scope, frame bool) *scope {
+	return &scope{
+		types:  make(map[string]ast.Type),
+		consts: make(map[string]bool),
+		values: make(map[string]object.Object),
+		bodies: make(map[string]*body),
+		outer:  outer,
+		frame:  frame,
+	}
+}
+
+func (s *scope) get(name string) (ast.Type, bool) {
+	for sc := s; sc != nil; sc = sc.outer {
+		if typ, ok := sc.types[name]; ok {
+			return typ, true
+		}
+	}
+	return unknown, false
+}
+
+// body returns the pending check of the function name refers to, or nil if
+// it does not refer to a declared function.
+func (s *scope) body(name string) *body {
+	for sc := s; sc != nil; sc = sc.outer {
+		if _, ok := sc.types[name]; ok {
+			return sc.bodies[name]
+		}
+	}
+	return nil
+}
+
+// value returns the value of the constant name refers to, if it is known
+// without running the program.
+func (s *scope) value(name string) (object.Object, bool) {
+	for sc := s; sc != nil; sc = sc.outer {
+		if _, ok := sc.types[name]; ok {
+			val, ok := sc.values[name]
+			return val, ok
+		}
+	}
+	return nil, false
+}
+
+func (s *scope) declared(name string) bool {
+	for sc := s; sc != nil; sc = sc.outer {
+		if _, ok := sc.types[name]; ok {
+			return true
+		}
+		if sc.frame {
+			return false
+		}
+	}
+	return false
+}
+
+func (s *scope) isConst(name string) bool {
+	for sc := s; sc != nil; sc = sc.outer {
+		if _, ok := sc.types[name]; ok {
+			return sc.consts[name]
+		}
+	}
+	return false
+}
+
+// names lists the names visible from s whose types satisfy keep, or all of
+// them if keep is nil.
+func (s *scope) names(keep func(ast.Type) bool) []string {
+	var names []string
+	for sc := s; sc != nil; sc = sc.outer {
+		for name, typ := range sc.types {
+			if keep == nil || keep(typ) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func (s *scope) declare(name string, typ ast.Type, isConst bool) {
+	s.types[name] = typ
+	s.consts[name] = isConst
+}
+
+// functionNames lists the functions that can be called from the current
+// scope.
+func (c *checker) functionNames() []string {
+	functions := c.scope.names(func(typ ast.Type) bool {
+		return typ.Return != nil
+	})
+	return append(functions, eval.BuiltinNames()...)
+}
+
+// errorf reports an error at the node being checked.
+func (c *checker) errorf(format string, a ...interface{}) {
+	c.errors = append(c.errors, object.Error{
+		Message: fmt.Sprintf(format, a...),
+		Pos:     c.pos,
+	})
+}
+
+// at moves the position errors are reported at to pos, returning a function
+// that moves it back.
+func (c *checker) at(pos ast.Pos) func() {
+	saved := c.pos
+	c.pos = pos
+	return func() { c.pos = saved }
+}
+
+func (c *checker) stmt(s ast.Statement) {
+	defer c.at(s.Position())()
+
+	switch s := s.(type) {
+	case ast.FuncDecl:
+		c.funcDecl(s)
+	case ast.VarDecl:
+		c.varDecl(s)
+	case ast.TupleDecl:
+		c.tupleDecl(s)
+	case ast.StructDecl:
+		c.structDecl(s)
+	case ast.EnumDecl:
+		c.enumDecl(s)
+	case ast.Block:
+		c.block(s)
+	case ast.While:
+		c.cond("while", s.Condition)
+		c.loop(s.Label, s.Body)
+	case ast.DoWhile:
+		c.loop(s.Label, s.Body)
+		c.cond("while", s.Condition)
+	case ast.For:
+		c.forLoop(s)
+	case ast.ForEach:
+		c.forEach(s)
+	case ast.IfElse:
+		c.cond("if", s.Condition)
+		c.stmt(s.Consequence)
+		if s.HasAlternative {
+			c.stmt(s.Alternative)
+		}
+	case ast.Switch:
+		c.switchStmt(s)
+	case ast.Return:
+		c.returnStmt(s)
+	case ast.Break:
+		c.breakStmt(s)
+	case ast.Continue:
+		c.continueStmt(s)
+	case ast.ExprStmt:
+		c.expr(s.Expression)
+	}
+}
+
+func (c *checker) funcDecl(fd ast.FuncDecl) {
+	name := fd.Ident.Name
+	if _, ok := c.scope.get(name); ok {
+		c.errorf("%s already declared", name)
+	}
+
+	typ := ast.FuncType(fd.Type, fd.Parameters)
+	c.validType(typ, name)
+	c.scope.declare(name, typ, false)
+	c.scope.bodies[name] = c.function(name, fd.Type, fd.Parameters, fd.Body)
+}
+
+// function defers checking the body of a function or lambda, in a frame
+// enclosing the current scope. The body runs only once called, so it is
+// checked at its first direct call, against the names declared by then, or
+// else at the end of the program.
+func (c *checker) function(name string, ret ast.Type, params []ast.Param, block ast.Block) *body {
+	outer, pos := c.scope, c.pos
+	b := &body{check: func() {
+		savedScope, savedFrame := c.scope, c.frame
+		c.scope = newScope(outer, true)
+		c.frame = &frame{name: name, ret: ret}
+
+		for _, param := range params {
+			c.scope.declare(param.Ident.Name, paramType(param), false)
+		}
+		c.block(block)
+
+		if ret.Value != "void" && !terminates(block) {
+			defer c.at(pos)()
+			c.errorf("missing return in %s()", name)
+		}
+
+		c.scope, c.frame = savedScope, savedFrame
+	}}
+	c.deferred = append(c.deferred, b)
+	return b
+}
+
+func (c *checker) checkBody(b *body) {
+	if !b.done {
+		b.done = true
+		b.check()
+	}
+}
+
+// terminates reports whether s cannot complete normally, as a return or a
+// loop that never ends does, so that a function whose body terminates
+// cannot fall off its end.
+func terminates(s ast.Statement) bool {
+	switch s := s.(type) {
+	case ast.Return:
+		return true
+	case ast.Block:
+		for _, stmt := range s.Statements {
+			if terminates(stmt) {
+				return true
+			}
+		}
+	case ast.IfElse:
+		return s.HasAlternative && terminates(s.Consequence) &&
+			terminates(s.Alternative)
+	case ast.While:
+		return isTrue(s.Condition) && !breaks(s.Body, s.Label.Name, false)
+	case ast.DoWhile:
+		return isTrue(s.Condition) && !breaks(s.Body, s.Label.Name, false)
+	case ast.For:
+		return isTrue(s.Condition) && !breaks(s.Body, s.Label.Name, false)
+	case ast.Switch:
+		hasDefault := false
+		for _, cs := range s.Cases {
+			hasDefault = hasDefault || cs.Default
+			if breaks(cs.Body, "", false) || !cs.Fallthrough && !terminates(cs.Body) {
+				return false
+			}
+		}
+		return hasDefault
+	}
+	return false
+}
+
+func isTrue(e ast.Expression) bool {
+	b, ok := e.(ast.Bool)
+	return ok && b.Value
+}
+
+// breaks reports whether s breaks out of the loop or switch labeled label
+// that it is the body of. Unlabeled breaks count only outside of any nested
+// loop or switch.
+func breaks(s ast.Statement, label string, nested bool) bool {
+	switch s := s.(type) {
+	case ast.Break:
+		if s.Label.Name == "" {
+			return !nested
+		}
+		return s.Label.Name == label
+	case ast.Block:
+		for _, stmt := range s.Statements {
+			if breaks(stmt, label, nested) {
+				return true
+			}
+		}
+	case ast.IfElse:
+		return breaks(s.Consequence, label, nested) ||
+			s.HasAlternative && breaks(s.Alternative, label, nested)
+	case ast.While:
+		return breaks(s.Body, label, true)
+	case ast.DoWhile:
+		return breaks(s.Body, label, true)
+	case ast.For:
+		return breaks(s.Body, label, true)
+	case ast.ForEach:
+		return breaks(s.Body, label, true)
+	case ast.Switch:
+		for _, cs := range s.Cases {
+			if breaks(cs.Body, label, true) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func paramType(param ast.Param) ast.Type {
+	if param.Array {
+		return ast.Type{Value: param.Type.Value + "arr"}
+	}
+	return param.Type
+}
+
+func (c *checker) varDecl(vd ast.VarDecl) {
+	name := vd.Ident.Name
+	if c.scope.declared(name) {
+		c.errorf("%s already declared", name)
+	}
+
+	valid := c.validType(vd.Type, name)
+	switch {
+	case vd.Initialized && vd.Type.Return != nil:
+		typ := c.value(vd.Value)
+		if known(typ) && typ.Value != vd.Type.Value {
+			c.errorf("mismatched types: %s %s = %s", vd.Type.Value, name, typ.Value)
+		}
+	case vd.Initialized:
+		if valid {
+			c.init(vd.Type, name, vd.Value)
+		} else {
+			c.expr(vd.Value)
+		}
+	default:
+		for _, dim := range vd.Dimensions {
+			if typ := c.value(dim); known(typ) && typ.Value != "int" {
+				c.errorf("array size must be integer")
+			}
+		}
+	}
+
+	c.scope.declare(name, vd.Type, vd.Const)
+	if vd.Const && vd.Initialized && ast.IsConstant(vd.Value) {
+		c.scope.values[name] = eval.Eval(vd.Value, object.NewState())
+	}
+}
+
+// init checks the initial value of a declaration against its type. Array
+// literals are checked element by element, or field by field for a struct.
+func (c *checker) init(typ ast.Type, name string, value ast.Expression) {
+	arr, ok := value.(ast.Array)
+	if !ok {
+		val := c.expr(value)
+		if val.Value == "void" {
+			c.errorf("mismatched types: %s %s = void", typ.Value, name)
+		} else if !assignable(typ, val, value) {
+			c.errorf("mismatched types: %s %s = %s", typ.Value, name, val.Value)
+		}
+		return
+	}
+
+	switch {
+	case isArrayType(typ.Value):
+		elementType := ast.Type{Value: strings.TrimSuffix(typ.Value, "arr")}
+		for _, element := range arr.Elements {
+			c.element(elementType, name, element)
+		}
+	case object.IsStructType(typ.Value):
+		fields, ok := c.structs[typ.Value]
+		if !ok {
+			return
+		}
+		if len(arr.Elements) > len(fields) {
+			c.errorf("too many values for struct %s",
+				strings.TrimPrefix(typ.Value, "struct "))
+		}
+		for i, element := range arr.Elements {
+			if i < len(fields) {
+				c.init(fields[i].Type, fields[i].Ident.Name, element)
+			}
+		}
+	default:
+		c.expr(arr)
+		c.errorf("mismatched types: %s %s = array", typ.Value, name)
+	}
+}
+
+// element checks one element of an array literal.
+func (c *checker) element(typ ast.Type, name string, element ast.Expression) {
+	defer c.at(element.Position())()
+
+	_, isLiteral := element.(ast.Array)
+	if object.IsStructType(typ.Value) || (isLiteral && isArrayType(typ.Value)) {
+		c.init(typ, name, element)
+		return
+	}
+
+	if val := c.value(element); !assignable(typ, val, element) {
+		c.errorf("illegal type in %s array: %s", typ.Value, val.Value)
+	}
+}
+
+func (c *checker) tupleDecl(td ast.TupleDecl) {
+	for i, decl := range td.Decls {
+		if c.scope.declared(decl.Ident.Name) {
+			c.errorf("%s already declared", decl.Ident.Name)
+		}
+		for _, prev := range td.Decls[:i] {
+			if prev.Ident.Name == decl.Ident.Name {
+				c.errorf("%s repeated in declaration", decl.Ident.Name)
+			}
+		}
+		c.validType(decl.Type, decl.Ident.Name)
+	}
+
+	val := c.value(td.Value)
+	switch {
+	case !known(val):
+	case len(val.Elements) == 0:
+		c.errorf("cannot unpack %s into %d variables",
+			ast.ExprString(td.Value), len(td.Decls))
+	case len(val.Elements) != len(td.Decls):
+		c.errorf("assignment mismatch: %d variables but %s returns %d values",
+			len(td.Decls), ast.ExprString(td.Value), len(val.Elements))
+	default:
+		for i, decl := range td.Decls {
+			if !assignable(decl.Type, val.Elements[i], ast.ElementOf(td.Value, i)) {
+				c.errorf("mismatched types: %s %s = %s",
+					decl.Type.Value, decl.Ident.Name, val.Elements[i].Value)
+			}
+		}
+	}
+
+	for _, decl := range td.Decls {
+		c.scope.declare(decl.Ident.Name, decl.Type, false)
+	}
+}
+
+// structDecl registers a struct's fields. Their types and defaults are only
+// evaluated when the struct is instantiated, so they are checked last.
+func (c *checker) structDecl(sd ast.StructDecl) {
+	typ := "struct " + sd.Ident.Name
+	if _, ok := c.structs[typ]; ok {
+		c.errorf("%s already declared", typ)
+		return
+	}
+	c.structs[typ] = sd.Fields
+
+	outer := c.scope
+	c.deferred = append(c.deferred, &body{check: func() {
+		saved := c.scope
+		c.scope = newScope(outer, true)
+
+		seen := make(map[string]bool)
+		for _, field := range sd.Fields {
+			c.pos = field.Pos
+			if seen[field.Ident.Name] {
+				c.errorf("field %s already declared in %s", field.Ident.Name, typ)
+				continue
+			}
+			seen[field.Ident.Name] = true
+
+			base := field.Type.Value
+			for strings.HasSuffix(base, "arr") && len(field.Dimensions) > 0 {
+				base = strings.TrimSuffix(base, "arr")
+			}
+			if base == typ {
+				c.errorf("%s cannot contain itself", typ)
+				continue
+			}
+
+			c.varDecl(field)
+		}
+
+		c.scope = saved
+	}})
+}
+
+func (c *checker) enumDecl(ed ast.EnumDecl) {
+	typ := "enum " + ed.Ident.Name
+	if _, ok := c.enums[typ]; ok {
+		c.errorf("%s already declared", typ)
+		return
+	}
+
+	members := make([]string, 0, len(ed.Members))
+	for _, member := range ed.Members {
+		if c.scope.declared(member.Name) {
+			c.errorf("identifier %s already declared", member.Name)
+		}
+		for _, prev := range members {
+			if prev == member.Name {
+				c.errorf("member %s already declared in %s", member.Name, typ)
+			}
+		}
+		members = append(members, member.Name)
+		c.scope.declare(member.Name, ast.Type{Value: typ}, true)
+		c.scope.values[member.Name] = object.Enum{
+			Name:   ed.Ident.Name,
+			Member: member.Name,
+			Value:  len(members) - 1,
+		}
+	}
+
+	c.enums[typ] = members
+}
+
+func (c *checker) block(b ast.Block) {
+	saved := c.scope
+	c.scope = newScope(saved, false)
+	for _, stmt := range b.Statements {
+		c.stmt(stmt)
+	}
+	c.scope = saved
+}
+
+func (c *checker) cond(kind string, e ast.Expression) {
+	if typ := c.value(e); known(typ) && typ.Value != "bool" {
+		c.errorf("improper %s condition type: %s", kind, typ.Value)
+	}
+}
+
+func (c *checker) loop(label ast.Identifier, body ast.Statement) {
+	c.frame.loops = append(c.frame.loops, label.Name)
+	c.stmt(body)
+	c.frame.loops = c.frame.loops[:len(c.frame.loops)-1]
+}
+
+func (c *checker) forLoop(f ast.For) {
+	saved := c.scope
+	c.scope = newScope(saved, false)
+
+	if f.VarDecl {
+		c.varDecl(ast.VarDecl{
+			Type:        f.Type,
+			Ident:       f.Ident,
+			Value:       f.Value,
+			Initialized: true,
+		})
+	} else {
+		c.expr(f.Init)
+	}
+
+	c.cond("for", f.Condition)
+	c.loop(f.Label, f.Body)
+	c.expr(f.Increment)
+
+	c.scope = saved
+}
+
+func (c *checker) forEach(fe ast.ForEach) {
+	collection := c.value(fe.Collection)
+
+	item := unknown
+	if key, _, ok := object.MapTypes(collection.Value); ok {
+		item = ast.Type{Value: key}
+	} else if isArrayType(collection.Value) {
+		item = ast.Type{Value: strings.TrimSuffix(collection.Value, "arr")}
+	} else if collection.Value == "string" {
+		item = ast.Type{Value: "char"}
+	} else if known(collection) && collection.Value != "array" {
+		c.errorf("cannot iterate over %s", collection.Value)
+	}
+
+	saved := c.scope
+	c.scope = newScope(saved, false)
+
+	if c.validType(fe.Type, fe.Ident.Name) && !assignable(fe.Type, item, nil) {
+		c.errorf("mismatched types: %s %s = %s",
+			fe.Type.Value, fe.Ident.Name, item.Value)
+	}
+	c.scope.declare(fe.Ident.Name, fe.Type, false)
+	c.loop(fe.Label, fe.Body)
+
+	c.scope = saved
+}
+
+func (c *checker) switchStmt(sw ast.Switch) {
+	val := c.value(sw.Value)
+	switch {
+	case !known(val), object.IsEnumType(val.Value), object.IsSized(val.Value):
+	case val.Value == "char", val.Value == "int", val.Value == "bigint",
+		val.Value == "string":
+	default:
+		c.errorf("improper switch value type: %s", val.Value)
+	}
+
+	defaults := 0
+	seen := make(map[string]bool)
+
+	for _, cs := range sw.Cases {
+		if cs.Default {
+			if defaults++; defaults == 2 {
+				c.errorf("multiple defaults in switch")
+			}
+		}
+
+		for _, expr := range cs.Values {
+			label := c.value(expr)
+			if !assignable(val, label, expr) {
+				c.errorf("mismatched types: switch %s case %s",
+					val.Value, label.Value)
+			}
+
+			if value, ok := eval.ConstantLabel(expr, c.scope.value); ok {
+				if seen[value.Eval()] {
+					c.errorf("duplicate case %s in switch", ast.ExprString(expr))
+				}
+				seen[value.Eval()] = true
+			}
+		}
+
+		c.frame.switches++
+		c.block(cs.Body)
+		c.frame.switches--
+	}
+}
+
+// returnStmt checks a return against the enclosing function's type. A
+// return at the top level ends the program and may return anything.
+func (c *checker) returnStmt(r ast.Return) {
+	val := void
+	if !r.Void {
+		val = c.expr(r.Value)
+	}
+
+	name, ret := c.frame.name, c.frame.ret
+	switch {
+	case !known(ret) || !known(val):
+	case ret.Value == "void":
+		if val.Value != "void" {
+			c.errorf("void function %s() returned %s", name, val.Value)
+		}
+	case val.Value == "void":
+		c.errorf("missing return value in %s()", name)
+	case len(ret.Elements) > 0 && len(val.Elements) > 0 &&
+		len(ret.Elements) != len(val.Elements):
+		c.errorf("%s() returns %d values, not %d",
+			name, len(ret.Elements), len(val.Elements))
+	case !assignable(ret, val, r.Value):
+		c.errorf("mismatched return type: %s %s() returned %s",
+			ret.Value, name, val.Value)
+	}
+}
+
+func (c *checker) breakStmt(b ast.Break) {
+	switch {
+	case b.Label.Name != "":
+		if !c.hasLoop(b.Label.Name) {
+			c.errorf("break label %s not defined", b.Label.Name)
+		}
+	case len(c.frame.loops) == 0 && c.frame.switches == 0:
+		c.errorf("break statement not within a loop")
+	}
+}
+
+func (c *checker) continueStmt(cs ast.Continue) {
+	switch {
+	case cs.Label.Name != "":
+		if !c.hasLoop(cs.Label.Name) {
+			c.errorf("continue label %s not defined", cs.Label.Name)
+		}
+	case len(c.frame.loops) == 0:
+		c.errorf("continue statement not within a loop")
+	}
+}
+
+func (c *checker) hasLoop(label string) bool {
+	for _, loop := range c.frame.loops {
+		if loop == label {
+			return true
+		}
+	}
+	return false
+}
+
+// value types an expression whose result is used, which a call to a void
+// function cannot provide.
+func (c *checker) value(e ast.Expression) ast.Type {
+	typ := c.expr(e)
+	if typ.Value == "void" {
+		c.errorf("%s (no value) used as value", ast.ExprString(e))
+		return unknown
+	}
+	return typ
+}
+
+func (c *checker) expr(e ast.Expression) ast.Type {
+	defer c.at(e.Position())()
+
+	switch e := e.(type) {
+	case ast.CharCon:
+		return ast.Type{Value: "char"}
+	case ast.IntCon:
+		return ast.Type{Value: "int"}
+	case ast.BigIntCon:
+		return ast.Type{Value: "bigint"}
+	case ast.FloatCon:
+		return ast.Type{Value: "float"}
+	case ast.StringCon:
+		return ast.Type{Value: "string"}
+	case ast.Bool:
+		return ast.Type{Value: "bool"}
+	case ast.Identifier:
+		return c.ident(e)
+	case ast.Array:
+		// Outside of a declaration an array literal has no element type,
+		// and only matches where any array is accepted.
+		for _, element := range e.Elements {
+			c.value(element)
+		}
+		return ast.Type{Value: "array"}
+	case ast.Tuple:
+		elements := make([]ast.Type, len(e.Elements))
+		for i, element := range e.Elements {
+			if elements[i] = c.expr(element); elements[i].Value == "void" {
+				c.errorf("void value in position %d of %s", i+1, ast.ExprString(e))
+				elements[i] = unknown
+			}
+		}
+		return ast.TupleType(elements)
+	case ast.PrefixExpr:
+		return c.prefix(e.Op, c.value(e.Right))
+	case ast.InfixExpr:
+		return c.infix(e.Op, c.value(e.Left), c.value(e.Right), e.Left, e.Right)
+	case ast.Ternary:
+		return c.ternary(e)
+	case ast.Cast:
+		return c.cast(e)
+	case ast.Assign:
+		c.assign(e)
+		return void
+	case ast.AssignExpr:
+		c.assignExpr(e)
+		return void
+	case ast.IncDecExpr:
+		c.checkConst(e.Ident)
+		return c.incDec(e.Op, c.ident(e.Ident))
+	case ast.IndexExpr:
+		return c.index(e.Left, e.Index, false)
+	case ast.SliceExpr:
+		return c.slice(e)
+	case ast.AssignIndexExpr:
+		c.compound("=", c.index(e.Left, e.Index, true), c.value(e.Value), e.Value)
+		return void
+	case ast.AssignExprIndexExpr:
+		c.compound(e.Op, c.index(e.Left, e.Index, true), c.value(e.Value), e.Value)
+		return void
+	case ast.IncDecIndexExpr:
+		return c.incDec(e.Op, c.index(e.Left, e.Index, true))
+	case ast.FieldExpr:
+		return c.field(e.Left, e.Field)
+	case ast.AssignExprFieldExpr:
+		c.checkConst(e.Left)
+		c.compound(e.Op, c.field(e.Left, e.Field), c.value(e.Value), e.Value)
+		return void
+	case ast.IncDecFieldExpr:
+		c.checkConst(e.Left)
+		return c.incDec(e.Op, c.field(e.Left, e.Field))
+	case ast.Call:
+		return c.call(e)
+	case ast.Lambda:
+		typ := ast.FuncType(e.Type, e.Parameters)
+		c.validType(typ, "lambda")
+		c.function("lambda", e.Type, e.Parameters, e.Body)
+		return typ
+	}
+	return unknown
+}
+
+func (c *checker) ident(i ast.Identifier) ast.Type {
+	if typ, ok := c.scope.get(i.Name); ok {
+		return typ
+	}
+	if !eval.IsBuiltin(i.Name) {
+		names := append(c.scope.names(nil), eval.BuiltinNames()...)
+		c.errorf("identifier %s undeclared%s",
+			i.Name, misc.DidYouMean(i.Name, names))
+	}
+	return unknown
+}
+
+func (c *checker) prefix(op string, right ast.Type) ast.Type {
+	if !known(right) {
+		return unknown
+	}
+
+	var ok bool
+	switch k := kind(right.Value); op {
+	case "!":
+		ok = k == "bool"
+	case "-", "+":
+		ok = k == "int" || k == "sized" || k == "bigint" || k == "float"
+	case "~":
+		ok = k == "int" || k == "sized" || k == "bigint"
+	}
+
+	if !ok {
+		c.errorf("illegal operation: %s%s", op, right.Value)
+		return unknown
+	}
+	return right
+}
+
+// infix types a binary operation. As with assignments, an integer literal
+// operand takes on the integer type of the other.
+func (c *checker) infix(op string, left, right ast.Type, leftExpr, rightExpr ast.Expression) ast.Type {
+	l, r := left.Value, right.Value
+	if !known(left) || !known(right) {
+		if isComparison(op) {
+			return ast.Type{Value: "bool"}
+		}
+		return unknown
+	}
+
+	if op == "<<" || op == ">>" {
+		switch {
+		case object.IsSized(l) || object.IsSized(r):
+			if r != "int" && !object.IsSized(r) {
+				c.errorf("illegal shift count: %s", r)
+				return unknown
+			}
+			if l != "int" && !object.IsSized(l) {
+				c.errorf("illegal operator: %s %s %s", l, op, r)
+				return unknown
+			}
+			return left
+		case l == "bigint":
+			if r != "int" {
+				c.errorf("illegal shift count: %s", r)
+				return unknown
+			}
+			return left
+		}
+	}
+
+	switch {
+	case object.IsInteger(l) && r == "int" && ast.IsIntLiteral(rightExpr):
+		r = l
+	case object.IsInteger(r) && l == "int" && ast.IsIntLiteral(leftExpr):
+		l, left = r, right
+	}
+
+	if l != r {
+		c.errorf("mismatched types: %s %s %s", l, op, r)
+		return unknown
+	}
+
+	ops, ok := operators[kind(l)]
+	if !ok {
+		c.errorf("invalid expression types: %s %s %s", l, op, r)
+		return unknown
+	}
+	if !contains(ops, op) {
+		c.errorf("illegal operator: %s %s %s", l, op, r)
+		return unknown
+	}
+
+	switch {
+	case isComparison(op):
+		return ast.Type{Value: "bool"}
+	case l == "char":
+		return ast.Type{Value: "string"}
+	default:
+		return left
+	}
+}
+
+func isComparison(op string) bool {
+	return contains([]string{"<", "<=", "==", "!=", ">=", ">", "&&", "||"}, op)
+}
+
+func (c *checker) ternary(t ast.Ternary) ast.Type {
+	c.cond("ternary", t.Condition)
+
+	consequence := c.value(t.Consequence)
+	alternative := c.value(t.Alternative)
+	if known(consequence) && known(alternative) &&
+		consequence.Value != alternative.Value {
+		c.errorf("mismatched types: %s : %s", consequence.Value, alternative.Value)
+	}
+
+	if known(consequence) {
+		return consequence
+	}
+	return alternative
+}
+
+func (c *checker) cast(cast ast.Cast) ast.Type {
+	val := c.expr(cast.Value)
+	switch {
+	case val.Value == "void":
+		c.errorf("cannot convert void to %s", cast.Type.Value)
+	case known(val) && !c.castable(val.Value, cast.Type.Value):
+		c.errorf("cannot convert %s to %s", val.Value, cast.Type.Value)
+	}
+	return cast.Type
+}
+
+// castable mirrors the conversions evalCast performs.
+func (c *checker) castable(from, to string) bool {
+	if from == to || to == "string" {
+		return true
+	}
+	if _, ok := c.enums[to]; !ok && object.IsEnumType(to) {
+		c.errorf("undeclared type: %s", to)
+		return true
+	}
+
+	switch {
+	case object.IsSized(from):
+		if to == "float" || to == "bigint" {
+			return true
+		}
+		from = "int"
+	case object.IsEnumType(from):
+		from = "int"
+	case from == "bigint":
+		if to == "float" || object.IsSized(to) {
+			return true
+		}
+		from = "int"
+	}
+
+	switch {
+	case to == "int", to == "bigint", object.IsSized(to):
+		return from == "int" || from == "float" || from == "char"
+	case to == "float", to == "char", object.IsEnumType(to):
+		return from == "int"
+	}
+	return false
+}
+
+func (c *checker) assign(a ast.Assign) {
+	c.checkConst(a.Ident)
+	self := c.ident(a.Ident)
+	if val := c.value(a.Value); !assignable(self, val, a.Value) {
+		c.errorf("assignment type mismatch: %s and %s", self.Value, val.Value)
+	}
+}
+
+func (c *checker) assignExpr(ae ast.AssignExpr) {
+	c.checkConst(ae.Ident)
+	self := c.ident(ae.Ident)
+	val := c.value(ae.Value)
+	if !assignable(self, val, ae.Value) {
+		c.errorf("mismatched types: %s %s %s", self.Value, ae.Op, val.Value)
+		return
+	}
+	c.arithmetic(ae.Op, self, val)
+}
+
+// compound checks an assignment such as "+=" to an array element or struct
+// field, as evalCompound does.
+func (c *checker) compound(op string, self, val ast.Type, e ast.Expression) {
+	if !assignable(self, val, e) {
+		c.errorf("assignment type mismatch: %s and %s", self.Value, val.Value)
+		return
+	}
+	if op != "=" {
+		c.arithmetic(op, self, val)
+	}
+}
+
+// arithmetic checks that the operator of an assignment such as "+=" applies
+// to the type being assigned.
+func (c *checker) arithmetic(op string, self, val ast.Type) {
+	if !known(self) {
+		return
+	}
+
+	infix := strings.TrimSuffix(op, "=")
+	switch kind(self.Value) {
+	case "sized", "bigint":
+		if infix == "<<" || infix == ">>" {
+			return
+		}
+		fallthrough
+	case "int", "float", "string":
+		c.infix(infix, self, self, nil, nil)
+	default:
+		c.errorf("illegal assignment: %s %s %s", self.Value, op, val.Value)
+	}
+}
+
+func (c *checker) incDec(op string, self ast.Type) ast.Type {
+	switch kind(self.Value) {
+	case "", "int", "sized", "bigint", "float":
+	default:
+		c.errorf("mismatched types: %s %s= int", self.Value, op[:1])
+	}
+	return self
+}
+
+// checkConst rejects assignments to a constant, or through an element or
+// field of one.
+func (c *checker) checkConst(left ast.Expression) {
+	for {
+		switch e := left.(type) {
+		case ast.IndexExpr:
+			left = e.Left
+		case ast.FieldExpr:
+			left = e.Left
+		case ast.Identifier:
+			if c.scope.isConst(e.Name) {
+				c.errorf("cannot assign to constant %s", e.Name)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
+
+// index types an element of an array, map or string. When target is set the
+// element is being assigned to.
+func (c *checker) index(left, index ast.Expression, target bool) ast.Type {
+	if target {
+		c.checkConst(left)
+	}
+
+	container := c.value(left)
+	key := c.value(index)
+
+	if keyType, valueType, ok := object.MapTypes(container.Value); ok {
+		if !assignable(ast.Type{Value: keyType}, key, index) {
+			c.errorf("illegal map key: %s", key.Value)
+		}
+		return ast.Type{Value: valueType}
+	}
+
+	switch {
+	case !known(container), container.Value == "array":
+		return unknown
+	case isArrayType(container.Value):
+		if known(key) && key.Value != "int" {
+			c.errorf("illegal array index: %s", key.Value)
+		}
+		return ast.Type{Value: strings.TrimSuffix(container.Value, "arr")}
+	case container.Value == "string":
+		if known(key) && key.Value != "int" {
+			c.errorf("illegal string index: %s", key.Value)
+		}
+		if target {
+			c.errorf("cannot assign to %s[%s]: strings are immutable",
+				ast.ExprString(left), ast.ExprString(index))
+		}
+		return ast.Type{Value: "char"}
+	default:
+		c.errorf("%s is not an array", ast.ExprString(left))
+		return unknown
+	}
+}
+
+func (c *checker) slice(se ast.SliceExpr) ast.Type {
+	if str := c.value(se.Left); known(str) && str.Value != "string" {
+		c.errorf("cannot slice %s", str.Value)
+	}
+
+	for _, bound := range []ast.Expression{se.Low, se.High} {
+		if bound == nil {
+			continue
+		}
+		if typ := c.value(bound); known(typ) && typ.Value != "int" {
+			c.errorf("illegal string index: %s", typ.Value)
+		}
+	}
+
+	return ast.Type{Value: "string"}
+}
+
+func (c *checker) field(left ast.Expression, field ast.Identifier) ast.Type {
+	st := c.value(left)
+	if !known(st) {
+		return unknown
+	}
+
+	fields, ok := c.structs[st.Value]
+	if !ok {
+		c.errorf("%s is not a struct", ast.ExprString(left))
+		return unknown
+	}
+
+	for _, f := range fields {
+		if f.Ident.Name == field.Name {
+			return f.Type
+		}
+	}
+
+	c.errorf("struct %s has no field %s",
+		strings.TrimPrefix(st.Value, "struct "), field.Name)
+	return unknown
+}
+
+func (c *checker) call(call ast.Call) ast.Type {
+	name := call.Function.Name
+	args := make([]ast.Type, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		args[i] = c.value(arg)
+	}
+
+	if b := c.scope.body(name); b != nil {
+		c.checkBody(b)
+	}
+
+	function, ok := c.scope.get(name)
+	if !ok {
+		if eval.IsBuiltin(name) {
+			return c.builtin(name, args, call.Arguments)
+		}
+		c.errorf("identifier %s undeclared%s",
+			name, misc.DidYouMean(name, c.functionNames()))
+		return unknown
+	}
+	if function.Return == nil {
+		if known(function) {
+			c.errorf("%s is not a declared or built-in function%s",
+				name, misc.DidYouMean(name, c.functionNames()))
+		}
+		return unknown
+	}
+
+	params := function.Parameters
+	if len(args) > len(params) {
+		c.errorf("too many arguments supplied to %s()", name)
+	} else if len(args) < len(params) {
+		c.errorf("not enough arguments supplied to %s()", name)
+	} else {
+		for i, param := range params {
+			arg := args[i]
+			if param.Array && known(arg) && !isArrayType(arg.Value) && arg.Value != "array" {
+				c.errorf("passed non-array as array parameter")
+			} else if !assignable(paramType(param), arg, call.Arguments[i]) {
+				c.errorf("mismatched types for argument %d", i+1)
+			}
+		}
+	}
+
+	return *function.Return
+}
+
+// builtin types a call to one of the built-in functions, checking its
+// arguments as the function itself would.
+func (c *checker) builtin(name string, args []ast.Type, exprs []ast.Expression) ast.Type {
+	if eval.Mutates(name) && len(exprs) > 0 {
+		c.checkConst(exprs[0])
+	}
+
+	switch name {
+	case "rand":
+		if len(args) != 0 {
+			c.errorf("too many arguments to rand()")
+		}
+		return ast.Type{Value: "int"}
+	case "len":
+		if len(args) != 1 {
+			c.errorf("len() takes exactly one argument")
+		} else if arg := args[0].Value; known(args[0]) && !isArrayType(arg) &&
+			!strings.HasPrefix(arg, "map<") && arg != "array" && arg != "string" {
+			c.errorf("len() of unsized type: %s", arg)
+		}
+		return ast.Type{Value: "int"}
+	case "push":
+		if len(args) != 2 {
+			c.errorf("push() takes an array and a value")
+			return void
+		}
+		c.elementArg(c.arrayArg(name, args[0]), args[1], exprs[1])
+	case "pop":
+		if len(args) != 1 {
+			c.errorf("pop() takes exactly one argument")
+			return unknown
+		}
+		return c.arrayArg(name, args[0])
+	case "insert":
+		if len(args) != 3 {
+			c.errorf("insert() takes an array, an index and a value")
+			return void
+		}
+		typ := c.arrayArg(name, args[0])
+		c.indexArg(args[1])
+		c.elementArg(typ, args[2], exprs[2])
+	case "remove":
+		if len(args) != 2 {
+			c.errorf("remove() takes an array and an index")
+			return unknown
+		}
+		c.indexArg(args[1])
+		return c.arrayArg(name, args[0])
+	case "has", "delete":
+		if len(args) != 2 {
+			c.errorf("%s() takes a map and a key", name)
+		} else if key, _, ok := object.MapTypes(args[0].Value); ok {
+			if !assignable(ast.Type{Value: key}, args[1], exprs[1]) {
+				c.errorf("illegal map key: %s", args[1].Value)
+			}
+		} else if known(args[0]) {
+			c.errorf("%s() expects a map, got %s", name, args[0].Value)
+		}
+		if name == "has" {
+			return ast.Type{Value: "bool"}
+		}
+	}
+	return void
+}
+
+// arrayArg checks the array argument of an array builtin, returning its
+// element type.
+func (c *checker) arrayArg(name string, arg ast.Type) ast.Type {
+	switch {
+	case isArrayType(arg.Value):
+		return ast.Type{Value: strings.TrimSuffix(arg.Value, "arr")}
+	case known(arg) && arg.Value != "array":
+		c.errorf("%s() expects an array, got %s", name, arg.Value)
+	}
+	return unknown
+}
+
+func (c *checker) indexArg(arg ast.Type) {
+	if known(arg) && arg.Value != "int" {
+		c.errorf("illegal array index: %s", arg.Value)
+	}
+}
+
+func (c *checker) elementArg(typ, val ast.Type, e ast.Expression) {
+	if !assignable(typ, val, e) {
+		c.errorf("illegal type in %s array: %s", typ.Value, val.Value)
+	}
+}
+
+// validType reports whether typ can be the type of a declared value,
+// reporting the struct, enum or map key that makes it invalid if not.
+func (c *checker) validType(typ ast.Type, name string) bool {
+	if typ.Return != nil {
+		valid := typ.Return.Value == "void" || c.validType(*typ.Return, name)
+		for _, param := range typ.Parameters {
+			valid = c.validType(paramType(param), param.Ident.Name) && valid
+		}
+		return valid
+	}
+
+	if len(typ.Elements) > 0 {
+		valid := true
+		for _, element := range typ.Elements {
+			valid = c.validType(element, name) && valid
+		}
+		return valid
+	}
+
+	base := typ.Value
+	for isArrayType(base) {
+		base = strings.TrimSuffix(base, "arr")
+	}
+
+	if key, value, ok := object.MapTypes(base); ok {
+		switch {
+		case key == "char", key == "int", key == "bigint", key == "string",
+			key == "bool", object.IsSized(key), object.IsEnumType(key):
+		default:
+			c.errorf("invalid map key type: %s", key)
+			return false
+		}
+		return c.validType(ast.Type{Value: key}, name) &&
+			c.validType(ast.Type{Value: value}, name)
+	}
+
+	switch {
+	case base == "char", base == "int", base == "bigint", base == "float",
+		base == "string", base == "bool", object.IsSized(base):
+		return true
+	case strings.HasSuffix(base, ")"):
+		// Function and tuple types nested in arrays and maps have
+		// only their names, so their parts cannot be checked.
+		return true
+	case object.IsStructType(base):
+		if _, ok := c.structs[base]; !ok {
+			c.errorf("undeclared type: %s", base)
+			return false
+		}
+		return true
+	case object.IsEnumType(base):
+		if _, ok := c.enums[base]; !ok {
+			c.errorf("undeclared type: %s", base)
+			return false
+		}
+		return true
+	default:
+		c.errorf("invalid declaration type: %s %s", typ.Value, name)
+		return false
+	}
+}
+
+// assignable reports whether a value of type from, computed by e, can be
+// stored where a value of type to is expected. Only integer literals convert
+// implicitly to fixed-width integers and bigints, as in coerce.
+func assignable(to, from ast.Type, e ast.Expression) bool {
+	if !known(to) || !known(from) {
+		return true
+	}
+
+	if len(to.Elements) > 0 && len(to.Elements) == len(from.Elements) {
+		for i := range to.Elements {
+			if !assignable(to.Elements[i], from.Elements[i], ast.ElementOf(e, i)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return to.Value == from.Value ||
+		(from.Value == "int" && object.IsInteger(to.Value) && ast.IsIntLiteral(e))
+}
+
+func known(typ ast.Type) bool {
+	return typ.Value != ""
+}
+
+// kind groups the fixed-width integer types and enums, which share their
+// operators.
+func kind(typ string) string {
+	switch {
+	case object.IsSized(typ):
+		return "sized"
+	case object.IsEnumType(typ):
+		return "enum"
+	default:
+		return typ
+	}
+}
+
+func isArrayType(typ string) bool {
+	return strings.HasSuffix(typ, "arr")
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
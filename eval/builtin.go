@@ -12,9 +12,11 @@
 package eval
 
 import (
+	"ariel/ast"
 	"ariel/object"
 	"fmt"
 	"math/rand"
+	"unicode/utf8"
 )
 
 var builtins = map[string]object.BuiltIn{
@@ -43,4 +45,219 @@ var builtins = map[string]object.BuiltIn{
 			return object.Int{Value: rand.Int63()}
 		},
 	},
+	"len": object.BuiltIn{
+		Function: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return errorObj("len() takes exactly one argument")
+			}
+			switch arg := args[0].(type) {
+			case *object.Array:
+				return object.Int{Value: int64(arg.Len())}
+			case object.Map:
+				return object.Int{Value: int64(len(arg.Keys))}
+			case object.String:
+				return object.Int{Value: int64(utf8.RuneCountInString(arg.Value))}
+			default:
+				return errorObj("len() of unsized type: %s",
+					object.ObjString(arg))
+			}
+		},
+	},
+	"push": object.BuiltIn{
+		Function: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return errorObj("push() takes an array and a value")
+			}
+			arr, err := arrayArg("push", args[0])
+			if err != nil {
+				return err
+			}
+			val, err := elementArg(arr, args[1])
+			if err != nil {
+				return err
+			}
+			arr.Push(val)
+			return nil
+		},
+		Element: 1,
+		Mutates: true,
+	},
+	"pop": object.BuiltIn{
+		Function: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return errorObj("pop() takes exactly one argument")
+			}
+			arr, err := arrayArg("pop", args[0])
+			if err != nil {
+				return err
+			}
+			if arr.Len() == 0 {
+				return errorObj("pop from empty array")
+			}
+			return arr.Pop()
+		},
+		Mutates: true,
+	},
+	"insert": object.BuiltIn{
+		Function: func(args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return errorObj("insert() takes an array, an index and a value")
+			}
+			arr, err := arrayArg("insert", args[0])
+			if err != nil {
+				return err
+			}
+			// Inserting at len(arr) appends, so the bound is inclusive.
+			i, err := indexArg("insert", args[1], arr.Len()+1)
+			if err != nil {
+				return err
+			}
+			val, err := elementArg(arr, args[2])
+			if err != nil {
+				return err
+			}
+			arr.Insert(i, val)
+			return nil
+		},
+		Element: 2,
+		Mutates: true,
+	},
+	"remove": object.BuiltIn{
+		Function: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return errorObj("remove() takes an array and an index")
+			}
+			arr, err := arrayArg("remove", args[0])
+			if err != nil {
+				return err
+			}
+			i, err := indexArg("remove", args[1], arr.Len())
+			if err != nil {
+				return err
+			}
+			return arr.Remove(i)
+		},
+		Mutates: true,
+	},
+	"has": object.BuiltIn{
+		Function: func(args ...object.Object) object.Object {
+			m, key, err := mapArgs("has", args)
+			if err != nil {
+				return err
+			}
+			_, ok := m.Get(key)
+			return object.Bool{Value: ok}
+		},
+		Element: 1,
+	},
+	"delete": object.BuiltIn{
+		Function: func(args ...object.Object) object.Object {
+			m, key, err := mapArgs("delete", args)
+			if err != nil {
+				return err
+			}
+			m.Delete(key)
+			return nil
+		},
+		Element: 1,
+		Mutates: true,
+	},
+}
+
+// arrayArg checks that the first argument to an array builtin is an array.
+func arrayArg(name string, arg object.Object) (*object.Array, object.Object) {
+	arr, ok := arg.(*object.Array)
+	if !ok {
+		return nil, errorObj("%s() expects an array, got %s",
+			name, object.ObjString(arg))
+	}
+	return arr, nil
+}
+
+// indexArg checks an index argument against the bound [0, limit).
+func indexArg(name string, arg object.Object, limit int) (int, object.Object) {
+	if arg == nil || arg.Type() != object.IntObj {
+		return 0, errorObj("illegal array index: %s", object.ObjString(arg))
+	}
+
+	idx := arg.(object.Int).Value
+	if idx < 0 || idx >= int64(limit) {
+		return 0, errorObj("array index out of bounds: %s(%d)", name, idx)
+	}
+
+	return int(idx), nil
+}
+
+// elementArg checks val against the element type of arr. A literal has
+// already been converted to it by coerceElement.
+func elementArg(arr *object.Array, val object.Object) (object.Object, object.Object) {
+	if val == nil {
+		return nil, errorObj("illegal type in %s array: void", arr.ElementType)
+	}
+	if arr.ElementType == "" {
+		return val, nil
+	}
+
+	if object.TypeName(val) != arr.ElementType {
+		return nil, errorObj("illegal type in %s array: %s",
+			arr.ElementType, object.TypeName(val))
+	}
+
+	return val, nil
+}
+
+// mapArgs unpacks the map and key arguments shared by has() and delete().
+func mapArgs(name string, args []object.Object) (object.Map, object.Object, object.Object) {
+	if len(args) != 2 {
+		return object.Map{}, nil, errorObj("%s() takes a map and a key", name)
+	}
+
+	m, ok := args[0].(object.Map)
+	if !ok {
+		return object.Map{}, nil, errorObj("%s() expects a map, got %s",
+			name, object.ObjString(args[0]))
+	}
+
+	key := args[1]
+	if object.TypeName(key) != m.KeyType {
+		return object.Map{}, nil, errorObj("illegal map key: %s",
+			object.TypeName(key))
+	}
+
+	return m, key, nil
+}
+
+// coerceElement converts val, the value of an integer literal e, to the
+// element type of the array or the key type of the map it is to be stored in
+// or looked up in.
+func coerceElement(container, val object.Object, e ast.Expression) object.Object {
+	switch container := container.(type) {
+	case *object.Array:
+		return coerce(val, container.ElementType, e)
+	case object.Map:
+		return coerce(val, container.KeyType, e)
+	default:
+		return val
+	}
+}
+
+// IsBuiltin reports whether name is one of the built-in functions.
+func IsBuiltin(name string) bool {
+	_, ok := builtins[name]
+	return ok
+}
+
+// Mutates reports whether the built-in function name modifies the container
+// passed to it first.
+func Mutates(name string) bool {
+	return builtins[name].Mutates
+}
+
+// BuiltinNames lists the built-in functions.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	return names
 }
@@ -13,18 +13,25 @@ package eval
 
 import (
 	"ariel/ast"
-	"ariel/color"
 	"ariel/misc"
 	"ariel/object"
 	"fmt"
 	"math"
+	"math/big"
+	"strings"
+	"unicode/utf8"
 )
 
 func errorObj(format string, a ...interface{}) object.Error {
-	msg := color.Red
-	msg += misc.Flounder("error: " + fmt.Sprintf(format, a...))
-	msg += color.Reset
-	return object.Error{Message: msg}
+	return object.Error{Message: fmt.Sprintf(format, a...)}
+}
+
+// errorAt is errorObj for an error that belongs to a node other than the one
+// being evaluated, such as a break outside of any loop.
+func errorAt(pos ast.Pos, format string, a ...interface{}) object.Error {
+	err := errorObj(format, a...)
+	err.Pos = pos
+	return err
 }
 
 func IsError(obj object.Object) bool {
@@ -34,7 +41,18 @@ func IsError(obj object.Object) bool {
 	return false
 }
 
+// Eval evaluates a node. An error raised while evaluating it is located at the
+// innermost node that was being evaluated.
 func Eval(n ast.Node, s *object.State) object.Object {
+	result := evalNode(n, s)
+	if err, ok := result.(object.Error); ok && err.Pos.Line == 0 {
+		err.Pos = n.Position()
+		return err
+	}
+	return result
+}
+
+func evalNode(n ast.Node, s *object.State) object.Object {
 	switch n := n.(type) {
 	case ast.Program:
 		return evalProgram(n, s)
@@ -42,40 +60,78 @@ func Eval(n ast.Node, s *object.State) object.Object {
 		return evalFuncDecl(n, s)
 	case ast.VarDecl:
 		return evalVarDecl(n, s)
+	case ast.TupleDecl:
+		return evalTupleDecl(n, s)
+	case ast.StructDecl:
+		return evalStructDecl(n, s)
+	case ast.EnumDecl:
+		return evalEnumDecl(n, s)
 	case ast.Block:
 		return evalBlock(n, s)
 	case ast.While:
 		return evalWhile(n, s)
+	case ast.DoWhile:
+		return evalDoWhile(n, s)
 	case ast.For:
 		return evalFor(n, s)
+	case ast.ForEach:
+		return evalForEach(n, s)
 	case ast.IfElse:
 		return evalIfElse(n, s)
+	case ast.Switch:
+		return evalSwitch(n, s)
 	case ast.Return:
 		return evalReturn(n, s)
+	case ast.Break:
+		return evalBreak(n)
+	case ast.Continue:
+		return evalContinue(n)
 	case ast.ExprStmt:
 		return evalExprStmt(n, s)
 	case ast.PrefixExpr:
 		return evalPrefixExpr(n, s)
 	case ast.InfixExpr:
 		return evalInfixExpr(n, s)
+	case ast.Ternary:
+		return evalTernary(n, s)
+	case ast.Cast:
+		return evalCast(n, s)
+	case ast.Tuple:
+		return evalTuple(n, s)
 	case ast.Assign:
 		return evalAssign(n, s)
 	case ast.AssignExpr:
 		return evalAssignExpr(n, s)
+	case ast.IncDecExpr:
+		return evalIncDecExpr(n, s)
 	case ast.IndexExpr:
 		return evalIndexExpr(n, s)
+	case ast.SliceExpr:
+		return evalSliceExpr(n, s)
 	case ast.AssignIndexExpr:
 		return evalAssignIndexExpr(n, s)
 	case ast.AssignExprIndexExpr:
 		return evalAssignExprIndexExpr(n, s)
+	case ast.IncDecIndexExpr:
+		return evalIncDecIndexExpr(n, s)
+	case ast.FieldExpr:
+		return evalFieldExpr(n, s)
+	case ast.AssignExprFieldExpr:
+		return evalAssignExprFieldExpr(n, s)
+	case ast.IncDecFieldExpr:
+		return evalIncDecFieldExpr(n, s)
 	case ast.Call:
 		return evalCall(n, s)
+	case ast.Lambda:
+		return evalLambda(n, s)
 	case ast.Array:
 		return evalArray(n, s)
 	case ast.CharCon:
 		return evalCharCon(n)
 	case ast.IntCon:
 		return evalIntCon(n)
+	case ast.BigIntCon:
+		return evalBigIntCon(n)
 	case ast.FloatCon:
 		return evalFloatCon(n)
 	case ast.StringCon:
@@ -98,6 +154,8 @@ func evalProgram(p ast.Program, s *object.State) object.Object {
 			return result.Value
 		case object.Error:
 			return result
+		case object.Break, object.Continue:
+			return loopControlError(result)
 		}
 	}
 	return result
@@ -121,10 +179,14 @@ func evalFuncDecl(fd ast.FuncDecl, s *object.State) object.Object {
 }
 
 func evalVarDecl(vd ast.VarDecl, s *object.State) object.Object {
-	if _, ok := s.Get(vd.Ident.Name); ok {
+	if s.Declared(vd.Ident.Name) {
 		return errorObj("%s already declared", vd.Ident.Name)
 	}
 
+	if vd.Type.Return != nil {
+		return evalFuncVarDecl(vd, s)
+	}
+
 	var val object.Object
 
 	if vd.Initialized {
@@ -133,105 +195,14 @@ func evalVarDecl(vd ast.VarDecl, s *object.State) object.Object {
 			return val
 		}
 
-		switch vd.Type.Value {
-		case "char":
-			if val.Type() != object.CharObj {
-				return errorObj("mismatched types: char %s = %s",
-					vd.Ident.Name, object.ObjString(val))
-			}
-		case "int":
-			if val.Type() != object.IntObj {
-				return errorObj("mismatched types: int %s = %s",
-					vd.Ident.Name, object.ObjString(val))
-			}
-		case "float":
-			if val.Type() != object.FloatObj {
-				return errorObj("mismatched types: float %s = %s",
-					vd.Ident.Name, object.ObjString(val))
-			}
-		case "string":
-			if val.Type() != object.StringObj {
-				return errorObj("mismatched types: string %s = %s",
-					vd.Ident.Name, object.ObjString(val))
-			}
-		case "bool":
-			if val.Type() != object.BoolObj {
-				return errorObj("mismatched types: bool %s = %s",
-					vd.Ident.Name, object.ObjString(val))
-			}
-		case "chararr":
-			val = Eval(vd.Value, s)
-			arr := val.(object.Array)
-			elements := val.(object.Array).Elements
-			val = object.Array{ElementType: "char", Elements: elements}
-			if !isHeterogeneous(arr) {
-				return errorObj("heterogeneous array typings: %s",
-					vd.Ident.Name)
-			}
-			if arr.Elements[0].Type() != object.CharObj {
-				return errorObj("illegal type in char array: %s",
-					object.ObjString(arr.Elements[0]))
-			}
-		case "intarr":
-			val = Eval(vd.Value, s)
-			arr := val.(object.Array)
-			elements := val.(object.Array).Elements
-			val = object.Array{ElementType: "int", Elements: elements}
-			if !isHeterogeneous(arr) {
-				return errorObj("heterogeneous array typings: %s",
-					vd.Ident.Name)
-			}
-			if arr.Elements[0].Type() != object.IntObj {
-				return errorObj("illegal type in int array: %s",
-					object.ObjString(arr.Elements[0]))
-			}
-		case "floatarr":
-			val = Eval(vd.Value, s)
-			arr := val.(object.Array)
-			elements := val.(object.Array).Elements
-			val = object.Array{ElementType: "float", Elements: elements}
-			if !isHeterogeneous(arr) {
-				return errorObj("heterogeneous array typings: %s",
-					vd.Ident.Name)
-			}
-			if arr.Elements[0].Type() != object.FloatObj {
-				return errorObj("illegal type in float array: %s",
-					object.ObjString(arr.Elements[0]))
-			}
-		case "stringarr":
-			val = Eval(vd.Value, s)
-			arr := val.(object.Array)
-			elements := val.(object.Array).Elements
-			val = object.Array{ElementType: "string", Elements: elements}
-			if !isHeterogeneous(arr) {
-				return errorObj("heterogeneous array typings: %s",
-					vd.Ident.Name)
-			}
-			if arr.Elements[0].Type() != object.StringObj {
-				return errorObj("illegal type in string array: %s",
-					object.ObjString(arr.Elements[0]))
-			}
-		case "boolarr":
-			val = Eval(vd.Value, s)
-			arr := val.(object.Array)
-			elements := val.(object.Array).Elements
-			val = object.Array{ElementType: "bool", Elements: elements}
-			if !isHeterogeneous(arr) {
-				return errorObj("heterogeneous array typings: %s",
-					vd.Ident.Name)
-			}
-			if arr.Elements[0].Type() != object.BoolObj {
-				return errorObj("illegal type in bool array: %s",
-					object.ObjString(arr.Elements[0]))
-			}
-		default:
-			return errorObj("invalid declaration type: %s %s = %s",
-				vd.Type.Value, vd.Ident.Name, object.ObjString(val))
+		val = checkDecl(vd.Type, vd.Ident.Name, val, vd.Value, s)
+		if IsError(val) {
+			return val
 		}
 	} else {
 		switch vd.Type.Value {
 		case "char":
-			val = object.Char{Value: ""}
+			val = object.Char{Value: 0}
 		case "int":
 			val = object.Int{Value: 0}
 		case "float":
@@ -240,87 +211,399 @@ func evalVarDecl(vd ast.VarDecl, s *object.State) object.Object {
 			val = object.String{Value: ""}
 		case "bool":
 			val = object.Bool{Value: false}
-		case "chararr":
-			elements := Eval(vd.Value, s)
-			if IsError(elements) {
-				return elements
-			}
-			if elements.Type() != object.IntObj {
-				return errorObj("array size must be integer")
-			}
-			numElements := elements.(object.Int).Value
-			arr := make([]object.Object, numElements)
-			for i := 0; i < int(numElements); i++ {
-				arr[i] = object.Char{Value: ""}
-			}
-			val = object.Array{ElementType: "char", Elements: arr}
-		case "intarr":
-			elements := Eval(vd.Value, s)
-			if IsError(elements) {
-				return elements
-			}
-			if elements.Type() != object.IntObj {
-				return errorObj("array size must be integer")
+		default:
+			if strings.HasSuffix(vd.Type.Value, "arr") {
+				val = evalArrayDecl(vd.Type.Value, vd.Dimensions, s)
+			} else {
+				val = zeroValue(vd.Type.Value, s)
 			}
-			numElements := elements.(object.Int).Value
-			arr := make([]object.Object, numElements)
-			for i := 0; i < int(numElements); i++ {
-				arr[i] = object.Int{Value: 0}
+			if val == nil {
+				return errorObj("invalid declaration type: %s %s",
+					vd.Type.Value, vd.Ident.Name)
 			}
-			val = object.Array{ElementType: "int", Elements: arr}
-		case "floatarr":
-			elements := Eval(vd.Value, s)
-			if IsError(elements) {
-				return elements
+			if IsError(val) {
+				return val
 			}
-			if elements.Type() != object.IntObj {
-				return errorObj("array size must be integer")
+		}
+	}
+
+	declare(vd, val, s)
+	return nil
+}
+
+// evalTupleDecl declares one variable for each of the values returned by a
+// function such as int q, r = divmod(7, 2).
+func evalTupleDecl(td ast.TupleDecl, s *object.State) object.Object {
+	for i, decl := range td.Decls {
+		if s.Declared(decl.Ident.Name) {
+			return errorObj("%s already declared", decl.Ident.Name)
+		}
+		for _, prev := range td.Decls[:i] {
+			if prev.Ident.Name == decl.Ident.Name {
+				return errorObj("%s repeated in declaration", decl.Ident.Name)
 			}
-			numElements := elements.(object.Int).Value
-			arr := make([]object.Object, numElements)
-			for i := 0; i < int(numElements); i++ {
-				arr[i] = object.Float{Value: 0.0}
+		}
+	}
+
+	val := Eval(td.Value, s)
+	if IsError(val) {
+		return val
+	}
+
+	tuple, ok := val.(object.Tuple)
+	if !ok {
+		return errorObj("cannot unpack %s into %d variables",
+			ast.ExprString(td.Value), len(td.Decls))
+	}
+	if len(tuple.Elements) != len(td.Decls) {
+		return errorObj("assignment mismatch: %d variables but %s returns %d values",
+			len(td.Decls), ast.ExprString(td.Value), len(tuple.Elements))
+	}
+
+	for i, decl := range td.Decls {
+		val := checkDecl(decl.Type, decl.Ident.Name, tuple.Elements[i],
+			ast.ElementOf(td.Value, i), s)
+		if IsError(val) {
+			return val
+		}
+		s.Set(decl.Ident.Name, val)
+	}
+
+	return nil
+}
+
+// declare binds the value of a declaration, as a constant if it was declared
+// const.
+func declare(vd ast.VarDecl, val object.Object, s *object.State) {
+	if vd.Const {
+		s.SetConst(vd.Ident.Name, val)
+	} else {
+		s.Set(vd.Ident.Name, val)
+	}
+}
+
+// checkDecl checks an initial value against the declared type of name,
+// returning the value to be stored or an error. e is the expression the value
+// came from, if it is known.
+func checkDecl(typ ast.Type, name string, val object.Object, e ast.Expression, s *object.State) object.Object {
+	if val == nil {
+		return errorObj("mismatched types: %s %s = void", typ.Value, name)
+	}
+
+	if val = coerce(val, typ.Value, e); IsError(val) {
+		return val
+	}
+
+	switch typ.Value {
+	case "char":
+		if val.Type() != object.CharObj {
+			return errorObj("mismatched types: char %s = %s",
+				name, object.ObjString(val))
+		}
+	case "int":
+		if val.Type() != object.IntObj {
+			return errorObj("mismatched types: int %s = %s",
+				name, object.ObjString(val))
+		}
+	case "float":
+		if val.Type() != object.FloatObj {
+			return errorObj("mismatched types: float %s = %s",
+				name, object.ObjString(val))
+		}
+	case "string":
+		if val.Type() != object.StringObj {
+			return errorObj("mismatched types: string %s = %s",
+				name, object.ObjString(val))
+		}
+	case "bool":
+		if val.Type() != object.BoolObj {
+			return errorObj("mismatched types: bool %s = %s",
+				name, object.ObjString(val))
+		}
+	default:
+		arr, ok := val.(*object.Array)
+		if ok && strings.HasSuffix(typ.Value, "arr") {
+			elementType := strings.TrimSuffix(typ.Value, "arr")
+			return evalArrayInit(name, elementType, arr, e, s)
+		}
+		if object.IsStructType(typ.Value) {
+			return evalStructInit(typ.Value, name, val, e, s)
+		}
+		if _, _, ok := object.MapTypes(typ.Value); ok || object.IsInteger(typ.Value) || object.IsEnumType(typ.Value) {
+			if object.TypeName(val) != typ.Value {
+				return errorObj("mismatched types: %s %s = %s",
+					typ.Value, name, object.TypeName(val))
 			}
-			val = object.Array{ElementType: "float", Elements: arr}
-		case "stringarr":
-			elements := Eval(vd.Value, s)
-			if IsError(elements) {
-				return elements
+			return val
+		}
+		return errorObj("invalid declaration type: %s %s = %s",
+			typ.Value, name, object.ObjString(val))
+	}
+
+	return val
+}
+
+func evalFuncVarDecl(vd ast.VarDecl, s *object.State) object.Object {
+	var val object.Object = object.FuncDecl{
+		ReturnType: *vd.Type.Return,
+		Ident:      vd.Ident,
+		Parameters: vd.Type.Parameters,
+	}
+
+	if vd.Initialized {
+		val = Eval(vd.Value, s)
+		if IsError(val) {
+			return val
+		}
+		if object.TypeName(val) != vd.Type.Value {
+			return errorObj("mismatched types: %s %s = %s",
+				vd.Type.Value, vd.Ident.Name, object.TypeName(val))
+		}
+	}
+
+	declare(vd, val, s)
+	return nil
+}
+
+// evalArrayInit checks every element of an array literal against the declared
+// element type, descending into the literals of nested dimensions.
+func evalArrayInit(name, elementType string, arr *object.Array, e ast.Expression, s *object.State) object.Object {
+	if arr.ElementType != "" && arr.ElementType != elementType {
+		return errorObj("mismatched types: %sarr %s = %s",
+			elementType, name, object.TypeName(arr))
+	}
+
+	if !isHeterogeneous(arr) {
+		return errorObj("heterogeneous array typings: %s", name)
+	}
+
+	innerType := strings.TrimSuffix(elementType, "arr")
+
+	for i, element := range arr.Elements {
+		inner, ok := element.(*object.Array)
+		if ok && innerType != elementType {
+			element = evalArrayInit(name, innerType, inner, ast.ElementOf(e, i), s)
+			if IsError(element) {
+				return element
 			}
-			if elements.Type() != object.IntObj {
-				return errorObj("array size must be integer")
+		} else if object.IsStructType(elementType) {
+			element = evalStructInit(elementType, name, element, ast.ElementOf(e, i), s)
+			if IsError(element) {
+				return element
 			}
-			numElements := elements.(object.Int).Value
-			arr := make([]object.Object, numElements)
-			for i := 0; i < int(numElements); i++ {
-				arr[i] = object.String{Value: ""}
+		} else if element = coerce(element, elementType, ast.ElementOf(e, i)); IsError(element) {
+			return element
+		} else if object.TypeName(element) != elementType {
+			return errorObj("illegal type in %s array: %s",
+				elementType, object.TypeName(element))
+		}
+		arr.Elements[i] = element
+	}
+
+	arr.ElementType = elementType
+	return arr
+}
+
+// evalArrayDecl builds a zeroed array of the given type with one size per
+// dimension. A declaration without sizes yields an empty array.
+func evalArrayDecl(typ string, dims []ast.Expression, s *object.State) object.Object {
+	elementType := strings.TrimSuffix(typ, "arr")
+	if len(dims) == 0 {
+		return &object.Array{ElementType: elementType, Elements: []object.Object{}}
+	}
+
+	size := Eval(dims[0], s)
+	if IsError(size) {
+		return size
+	}
+	if size.Type() != object.IntObj {
+		return errorObj("array size must be integer")
+	}
+
+	numElements := size.(object.Int).Value
+	if numElements < 0 {
+		return errorObj("array size must be non-negative")
+	}
+
+	arr := make([]object.Object, numElements)
+	for i := range arr {
+		if len(dims) > 1 {
+			arr[i] = evalArrayDecl(elementType, dims[1:], s)
+		} else {
+			arr[i] = zeroValue(elementType, s)
+		}
+		if arr[i] == nil {
+			return errorObj("invalid array type: %s", elementType)
+		}
+		if IsError(arr[i]) {
+			return arr[i]
+		}
+	}
+
+	return &object.Array{ElementType: elementType, Elements: arr}
+}
+
+func zeroValue(typ string, s *object.State) object.Object {
+	switch typ {
+	case "char":
+		return object.Char{Value: 0}
+	case "int":
+		return object.Int{Value: 0}
+	case "bigint":
+		return object.BigInt{Value: new(big.Int)}
+	case "float":
+		return object.Float{Value: 0.0}
+	case "string":
+		return object.String{Value: ""}
+	case "bool":
+		return object.Bool{Value: false}
+	default:
+		if object.IsSized(typ) {
+			return object.NewSized(typ, 0)
+		}
+		if key, value, ok := object.MapTypes(typ); ok {
+			switch key {
+			case "char", "int", "bigint", "string", "bool":
+				return object.NewMap(key, value)
 			}
-			val = object.Array{ElementType: "string", Elements: arr}
-		case "boolarr":
-			elements := Eval(vd.Value, s)
-			if IsError(elements) {
-				return elements
+			if object.IsSized(key) || object.IsEnumType(key) {
+				return object.NewMap(key, value)
 			}
-			if elements.Type() != object.IntObj {
-				return errorObj("array size must be integer")
+			return errorObj("invalid map key type: %s", key)
+		}
+		if strings.HasSuffix(typ, "arr") {
+			return &object.Array{
+				ElementType: strings.TrimSuffix(typ, "arr"),
+				Elements:    []object.Object{},
 			}
-			numElements := elements.(object.Int).Value
-			arr := make([]object.Object, numElements)
-			for i := 0; i < int(numElements); i++ {
-				arr[i] = object.Bool{Value: false}
+		}
+		if decl, ok := s.Get(typ); ok && decl.Type() == object.StructDeclObj {
+			return evalStruct(decl.(object.StructDecl), nil, nil)
+		}
+		if decl, ok := s.Get(typ); ok && decl.Type() == object.EnumDeclObj {
+			return decl.(object.EnumDecl).Members[0]
+		}
+		return nil
+	}
+}
+
+func evalStructDecl(sd ast.StructDecl, s *object.State) object.Object {
+	typ := "struct " + sd.Ident.Name
+	if _, ok := s.Get(typ); ok {
+		return errorObj("%s already declared", typ)
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range sd.Fields {
+		if seen[field.Ident.Name] {
+			return errorObj("field %s already declared in %s",
+				field.Ident.Name, typ)
+		}
+		seen[field.Ident.Name] = true
+
+		base := field.Type.Value
+		for strings.HasSuffix(base, "arr") && len(field.Dimensions) > 0 {
+			base = strings.TrimSuffix(base, "arr")
+		}
+		if base == typ {
+			return errorObj("%s cannot contain itself", typ)
+		}
+	}
+
+	decl := object.StructDecl{
+		Ident:  sd.Ident,
+		Fields: sd.Fields,
+		State:  s,
+	}
+
+	s.Set(typ, decl)
+	return nil
+}
+
+// evalEnumDecl declares an enumerated type along with a constant for each of
+// its members.
+func evalEnumDecl(ed ast.EnumDecl, s *object.State) object.Object {
+	typ := "enum " + ed.Ident.Name
+	if _, ok := s.Get(typ); ok {
+		return errorObj("%s already declared", typ)
+	}
+
+	members := make([]object.Enum, len(ed.Members))
+	for i, member := range ed.Members {
+		if s.Declared(member.Name) {
+			return errorObj("identifier %s already declared", member.Name)
+		}
+		for _, prev := range members[:i] {
+			if prev.Member == member.Name {
+				return errorObj("member %s already declared in %s",
+					member.Name, typ)
 			}
-			val = object.Array{ElementType: "bool", Elements: arr}
-		default:
-			return errorObj("invalid declaration type: %s %s = %s",
-				vd.Type.Value, vd.Ident.Name, object.ObjString(val))
 		}
+		members[i] = object.Enum{
+			Name:   ed.Ident.Name,
+			Member: member.Name,
+			Value:  i,
+		}
+	}
+
+	for _, member := range members {
+		s.SetConst(member.Member, member)
 	}
 
-	s.Set(vd.Ident.Name, val)
+	s.Set(typ, object.EnumDecl{Ident: ed.Ident, Members: members})
 	return nil
 }
 
-func isHeterogeneous(arr object.Array) bool {
+// evalStruct instantiates a struct, checking values, which came from the
+// elements of e, against its fields in order. Fields without a value get the
+// default from their declaration.
+func evalStruct(decl object.StructDecl, values []object.Object, e ast.Expression) object.Object {
+	if len(values) > len(decl.Fields) {
+		return errorObj("too many values for struct %s", decl.Ident.Name)
+	}
+
+	fields := make([]string, len(decl.Fields))
+	vals := make(map[string]object.Object)
+	scratch := object.NewFrameState(decl.State)
+
+	for i, field := range decl.Fields {
+		var val object.Object
+		if i < len(values) {
+			val = checkDecl(field.Type, field.Ident.Name, values[i],
+				ast.ElementOf(e, i), scratch)
+		} else if val = evalVarDecl(field, scratch); val == nil {
+			val, _ = scratch.Get(field.Ident.Name)
+		}
+		if IsError(val) {
+			return val
+		}
+
+		fields[i] = field.Ident.Name
+		vals[field.Ident.Name] = val
+	}
+
+	return object.Struct{Name: decl.Ident.Name, Fields: fields, Values: vals}
+}
+
+func evalStructInit(typ, name string, val object.Object, e ast.Expression, s *object.State) object.Object {
+	decl, ok := s.Get(typ)
+	if !ok || decl.Type() != object.StructDeclObj {
+		return errorObj("undeclared type: %s", typ)
+	}
+
+	if arr, ok := val.(*object.Array); ok && arr.ElementType == "" {
+		return evalStruct(decl.(object.StructDecl), arr.Elements, e)
+	}
+
+	if object.TypeName(val) != typ {
+		return errorObj("mismatched types: %s %s = %s",
+			typ, name, object.TypeName(val))
+	}
+
+	return val
+}
+
+func isHeterogeneous(arr *object.Array) bool {
 	if len(arr.Elements) > 0 {
 		for _, element := range arr.Elements {
 			if element.Type() != arr.Elements[0].Type() {
@@ -333,21 +616,22 @@ func isHeterogeneous(arr object.Array) bool {
 
 func evalBlock(b ast.Block, s *object.State) object.Object {
 	var result object.Object
-	copied := object.NewCopiedState(s)
+	enclosed := object.NewEnclosedState(s)
 
 	for _, stmt := range b.Statements {
-		result = Eval(stmt, copied)
+		result = Eval(stmt, enclosed)
 		if result != nil {
 			switch result := result.(type) {
 			case object.Return:
 				return result
 			case object.Error:
 				return result
+			case object.Break, object.Continue:
+				return result
 			}
 		}
 	}
 
-	object.UpdateState(s, copied)
 	return result
 }
 
@@ -368,9 +652,18 @@ func evalWhile(w ast.While, s *object.State) object.Object {
 		result := Eval(w.Body, s)
 		switch result := result.(type) {
 		case object.Return:
-			return result.Value
+			return result
 		case object.Error:
 			return result
+		case object.Break:
+			if !targetsLoop(result.Label, w.Label) {
+				return result
+			}
+			return nil
+		case object.Continue:
+			if !targetsLoop(result.Label, w.Label) {
+				return result
+			}
 		}
 		cond = Eval(w.Condition, s)
 	}
@@ -378,9 +671,44 @@ func evalWhile(w ast.While, s *object.State) object.Object {
 	return result
 }
 
+func evalDoWhile(dw ast.DoWhile, s *object.State) object.Object {
+	for {
+		result := Eval(dw.Body, s)
+		switch result := result.(type) {
+		case object.Return:
+			return result
+		case object.Error:
+			return result
+		case object.Break:
+			if !targetsLoop(result.Label, dw.Label) {
+				return result
+			}
+			return nil
+		case object.Continue:
+			if !targetsLoop(result.Label, dw.Label) {
+				return result
+			}
+		}
+
+		cond := Eval(dw.Condition, s)
+		if IsError(cond) {
+			return cond
+		}
+
+		if cond.Type() != object.BoolObj {
+			return errorObj("improper while condition type: %s",
+				object.ObjString(cond))
+		}
+
+		if !cond.(object.Bool).Value {
+			return nil
+		}
+	}
+}
+
 func evalFor(f ast.For, s *object.State) object.Object {
 	var result object.Object
-	copied := object.NewCopiedState(s)
+	enclosed := object.NewEnclosedState(s)
 
 	if f.VarDecl {
 		vardecl := ast.VarDecl{
@@ -389,18 +717,18 @@ func evalFor(f ast.For, s *object.State) object.Object {
 			Value:       f.Value,
 			Initialized: true,
 		}
-		declared := Eval(vardecl, copied)
+		declared := Eval(vardecl, enclosed)
 		if IsError(declared) {
 			return declared
 		}
 	} else {
-		init := Eval(f.Init, copied)
+		init := Eval(f.Init, enclosed)
 		if IsError(init) {
 			return init
 		}
 	}
 
-	cond := Eval(f.Condition, copied)
+	cond := Eval(f.Condition, enclosed)
 	if IsError(cond) {
 		return cond
 	}
@@ -411,29 +739,93 @@ func evalFor(f ast.For, s *object.State) object.Object {
 	}
 
 	for cond.(object.Bool).Value {
-		result := Eval(f.Body, copied)
+		result := Eval(f.Body, enclosed)
 		switch result := result.(type) {
 		case object.Return:
-			return result.Value
+			return result
 		case object.Error:
 			return result
+		case object.Break:
+			if !targetsLoop(result.Label, f.Label) {
+				return result
+			}
+			return nil
+		case object.Continue:
+			if !targetsLoop(result.Label, f.Label) {
+				return result
+			}
 		}
 
-		increment := Eval(f.Increment, copied)
+		increment := Eval(f.Increment, enclosed)
 		if IsError(increment) {
 			return increment
 		}
 
-		cond = Eval(f.Condition, copied)
+		cond = Eval(f.Condition, enclosed)
 		if IsError(cond) {
 			return cond
 		}
 	}
 
-	object.UpdateState(s, copied)
 	return result
 }
 
+// evalForEach iterates over the elements of an array, the keys of a map in
+// sorted order or the characters of a string, binding each to the loop
+// variable in a fresh scope.
+func evalForEach(fe ast.ForEach, s *object.State) object.Object {
+	collection := Eval(fe.Collection, s)
+	if IsError(collection) {
+		return collection
+	}
+
+	var items []object.Object
+	switch collection := collection.(type) {
+	case *object.Array:
+		items = collection.Elements
+	case object.Map:
+		items = collection.SortedKeys()
+	case object.String:
+		for _, c := range collection.Value {
+			items = append(items, object.Char{Value: c})
+		}
+	default:
+		return errorObj("cannot iterate over %s", object.ObjString(collection))
+	}
+
+	for _, item := range items {
+		enclosed := object.NewEnclosedState(s)
+		val := checkDecl(fe.Type, fe.Ident.Name, item, nil, enclosed)
+		if IsError(val) {
+			return val
+		}
+		enclosed.Set(fe.Ident.Name, val)
+
+		result := Eval(fe.Body, enclosed)
+		switch result := result.(type) {
+		case object.Return:
+			return result
+		case object.Error:
+			return result
+		case object.Break:
+			if !targetsLoop(result.Label, fe.Label) {
+				return result
+			}
+			return nil
+		case object.Continue:
+			if !targetsLoop(result.Label, fe.Label) {
+				return result
+			}
+		}
+	}
+
+	return nil
+}
+
+func targetsLoop(label string, loop ast.Identifier) bool {
+	return label == "" || label == loop.Name
+}
+
 func evalIfElse(ie ast.IfElse, s *object.State) object.Object {
 	cond := Eval(ie.Condition, s)
 	if IsError(cond) {
@@ -454,32 +846,190 @@ func evalIfElse(ie ast.IfElse, s *object.State) object.Object {
 	}
 }
 
-func evalReturn(ie ast.Return, s *object.State) object.Object {
-	if !ie.Void {
-		val := Eval(ie.Value, s)
-		if IsError(val) {
-			return val
-		}
-		return object.Return{Value: val}
+func evalSwitch(sw ast.Switch, s *object.State) object.Object {
+	val := Eval(sw.Value, s)
+	if IsError(val) {
+		return val
 	}
-	return nil
-}
-
-func evalExprStmt(es ast.ExprStmt, s *object.State) object.Object {
-	return Eval(es.Expression, s)
-}
 
-func evalPrefixExpr(pe ast.PrefixExpr, s *object.State) object.Object {
-	right := Eval(pe.Right, s)
-	if IsError(right) {
-		return right
+	switch val.Type() {
+	case object.CharObj, object.IntObj, object.SizedObj, object.BigIntObj,
+		object.StringObj, object.EnumObj:
+	default:
+		return errorObj("improper switch value type: %s",
+			object.ObjString(val))
 	}
 
-	switch pe.Op {
-	case "!":
-		return evalNotOp(right)
-	case "-":
-		return evalNegOp(right)
+	fallback := -1
+	seen := make(map[string]bool)
+
+	// The cases are checked before any label with side effects is evaluated
+	// or any body is run, so that a malformed switch does nothing.
+	for i, c := range sw.Cases {
+		if c.Default {
+			if fallback >= 0 {
+				return errorObj("multiple defaults in switch")
+			}
+			fallback = i
+			continue
+		}
+
+		for _, expr := range c.Values {
+			label, ok := ConstantLabel(expr, func(name string) (object.Object, bool) {
+				if !s.Const(name) {
+					return nil, false
+				}
+				return s.Get(name)
+			})
+			if !ok {
+				continue
+			}
+			if label = checkLabel(label, val, expr); IsError(label) {
+				return label
+			}
+			if seen[label.Eval()] {
+				return errorObj("duplicate case %s in switch", label.Eval())
+			}
+			seen[label.Eval()] = true
+		}
+	}
+
+	matched, err := matchCase(sw, val, s)
+	if err != nil {
+		return err
+	}
+	if matched < 0 {
+		matched = fallback
+	}
+	if matched < 0 {
+		return nil
+	}
+
+	for i := matched; i < len(sw.Cases); i++ {
+		result := Eval(sw.Cases[i].Body, s)
+		switch result := result.(type) {
+		case object.Break:
+			if result.Label != "" {
+				return result
+			}
+			return nil
+		case object.Return, object.Continue, object.Error:
+			return result
+		}
+
+		if !sw.Cases[i].Fallthrough {
+			break
+		}
+	}
+
+	return nil
+}
+
+// ConstantLabel returns the value of a case label if it is known before the
+// switch runs, as for a literal or a name declared const, such as an enum
+// member. constant looks up the value of such a name. Both the checker and
+// the evaluator find duplicate labels by comparing these values.
+func ConstantLabel(e ast.Expression, constant func(name string) (object.Object, bool)) (object.Object, bool) {
+	if ident, ok := e.(ast.Identifier); ok {
+		return constant(ident.Name)
+	}
+	if ast.IsConstant(e) {
+		return Eval(e, object.NewState()), true
+	}
+	return nil, false
+}
+
+// matchCase returns the index of the first case with a label equal to val,
+// or -1 if there is none. Labels are evaluated in order only until one
+// matches.
+func matchCase(sw ast.Switch, val object.Object, s *object.State) (int, object.Object) {
+	for i, c := range sw.Cases {
+		for _, expr := range c.Values {
+			label := evalLabel(expr, val, s)
+			if IsError(label) {
+				return -1, label
+			}
+			if label.Eval() == val.Eval() {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// evalLabel evaluates a case label, which must have the type of the switch
+// value val.
+func evalLabel(expr ast.Expression, val object.Object, s *object.State) object.Object {
+	return checkLabel(Eval(expr, s), val, expr)
+}
+
+// checkLabel converts label, the value of expr, to the type of the switch
+// value val, or reports that it has a different type.
+func checkLabel(label, val object.Object, expr ast.Expression) object.Object {
+	label = coerce(label, object.TypeName(val), expr)
+	if IsError(label) {
+		return label
+	}
+
+	if object.TypeName(label) != object.TypeName(val) {
+		return errorObj("mismatched types: switch %s case %s",
+			object.TypeName(val), object.TypeName(label))
+	}
+
+	return label
+}
+
+func evalReturn(ie ast.Return, s *object.State) object.Object {
+	if !ie.Void {
+		val := Eval(ie.Value, s)
+		if IsError(val) {
+			return val
+		}
+		return object.Return{Value: val, Expr: ie.Value}
+	}
+	return object.Return{}
+}
+
+func evalBreak(b ast.Break) object.Object {
+	return object.Break{Label: b.Label.Name, Pos: b.Pos}
+}
+
+func evalContinue(c ast.Continue) object.Object {
+	return object.Continue{Label: c.Label.Name, Pos: c.Pos}
+}
+
+func loopControlError(obj object.Object) object.Object {
+	switch obj := obj.(type) {
+	case object.Break:
+		if obj.Label != "" {
+			return errorAt(obj.Pos, "break label %s not defined", obj.Label)
+		}
+		return errorAt(obj.Pos, "break statement not within a loop")
+	case object.Continue:
+		if obj.Label != "" {
+			return errorAt(obj.Pos, "continue label %s not defined", obj.Label)
+		}
+		return errorAt(obj.Pos, "continue statement not within a loop")
+	default:
+		return obj
+	}
+}
+
+func evalExprStmt(es ast.ExprStmt, s *object.State) object.Object {
+	return Eval(es.Expression, s)
+}
+
+func evalPrefixExpr(pe ast.PrefixExpr, s *object.State) object.Object {
+	right := Eval(pe.Right, s)
+	if IsError(right) {
+		return right
+	}
+
+	switch pe.Op {
+	case "!":
+		return evalNotOp(right)
+	case "-":
+		return evalNegOp(right)
 	case "+":
 		return evalPosOp(right)
 	case "~":
@@ -503,6 +1053,10 @@ func evalNegOp(expr object.Object) object.Object {
 	switch expr := expr.(type) {
 	case object.Int:
 		return object.Int{Value: -expr.Value}
+	case object.Sized:
+		return object.NewSized(expr.Name, -expr.Value)
+	case object.BigInt:
+		return object.BigInt{Value: new(big.Int).Neg(expr.Value)}
 	case object.Float:
 		return object.Float{Value: -expr.Value}
 	default:
@@ -518,6 +1072,13 @@ func evalPosOp(expr object.Object) object.Object {
 			abs = -abs
 		}
 		return object.Int{Value: abs}
+	case object.Sized:
+		if expr.Int() < 0 {
+			return object.NewSized(expr.Name, -expr.Value)
+		}
+		return expr
+	case object.BigInt:
+		return object.BigInt{Value: new(big.Int).Abs(expr.Value)}
 	case object.Float:
 		return object.Float{Value: math.Abs(expr.Value)}
 	default:
@@ -529,6 +1090,10 @@ func evalTildeOp(expr object.Object) object.Object {
 	switch expr := expr.(type) {
 	case object.Int:
 		return object.Int{Value: ^expr.Value}
+	case object.Sized:
+		return object.NewSized(expr.Name, ^expr.Value)
+	case object.BigInt:
+		return object.BigInt{Value: new(big.Int).Not(expr.Value)}
 	default:
 		return errorObj("illegal operation: ~%s", object.ObjString(expr))
 	}
@@ -540,14 +1105,45 @@ func evalInfixExpr(ie ast.InfixExpr, s *object.State) object.Object {
 		return left
 	}
 
+	if left.Type() == object.BoolObj {
+		switch {
+		case ie.Op == "&&" && !left.(object.Bool).Value:
+			return left
+		case ie.Op == "||" && left.(object.Bool).Value:
+			return left
+		}
+	}
+
 	right := Eval(ie.Right, s)
 	if IsError(right) {
 		return right
 	}
 
-	if left.Type() != right.Type() {
+	if ie.Op == "<<" || ie.Op == ">>" {
+		if left.Type() == object.SizedObj || right.Type() == object.SizedObj {
+			return evalShiftSized(ie.Op, left, right)
+		}
+		if left.Type() == object.BigIntObj {
+			return evalShiftBig(ie.Op, left.(object.BigInt), right)
+		}
+	}
+
+	switch {
+	case object.IsInteger(object.TypeName(left)):
+		right = coerce(right, object.TypeName(left), ie.Right)
+	case object.IsInteger(object.TypeName(right)):
+		left = coerce(left, object.TypeName(right), ie.Left)
+	}
+	if IsError(left) {
+		return left
+	}
+	if IsError(right) {
+		return right
+	}
+
+	if object.TypeName(left) != object.TypeName(right) {
 		return errorObj("mismatched types: %s %s %s",
-			object.ObjString(left), ie.Op, object.ObjString(right))
+			object.TypeName(left), ie.Op, object.TypeName(right))
 	}
 
 	switch right.Type() {
@@ -555,6 +1151,12 @@ func evalInfixExpr(ie ast.InfixExpr, s *object.State) object.Object {
 		return evalInfixExprChar(ie.Op, left.(object.Char), right.(object.Char))
 	case object.IntObj:
 		return evalInfixExprInt(ie.Op, left.(object.Int), right.(object.Int))
+	case object.SizedObj:
+		return evalInfixExprSized(ie.Op, left.(object.Sized), right.(object.Sized))
+	case object.BigIntObj:
+		return evalInfixExprBig(ie.Op, left.(object.BigInt), right.(object.BigInt))
+	case object.EnumObj:
+		return evalInfixExprEnum(ie.Op, left.(object.Enum), right.(object.Enum))
 	case object.FloatObj:
 		return evalInfixExprFloat(ie.Op, left.(object.Float), right.(object.Float))
 	case object.StringObj:
@@ -582,13 +1184,35 @@ func evalInfixExprChar(op string, left, right object.Char) object.Object {
 	case ">":
 		return object.Bool{Value: left.Value > right.Value}
 	case "+":
-		return object.String{Value: left.Value + right.Value}
+		return object.String{Value: string(left.Value) + string(right.Value)}
 	default:
 		return errorObj("illegal operator: %s %s %s",
 			object.ObjString(left), op, object.ObjString(right))
 	}
 }
 
+// evalInfixExprEnum compares members of the same enum by their order in its
+// declaration.
+func evalInfixExprEnum(op string, left, right object.Enum) object.Object {
+	switch op {
+	case "<":
+		return object.Bool{Value: left.Value < right.Value}
+	case "<=":
+		return object.Bool{Value: left.Value <= right.Value}
+	case "==":
+		return object.Bool{Value: left.Value == right.Value}
+	case "!=":
+		return object.Bool{Value: left.Value != right.Value}
+	case ">=":
+		return object.Bool{Value: left.Value >= right.Value}
+	case ">":
+		return object.Bool{Value: left.Value > right.Value}
+	default:
+		return errorObj("illegal operator: %s %s %s",
+			object.TypeName(left), op, object.TypeName(right))
+	}
+}
+
 func evalInfixExprInt(op string, left, right object.Int) object.Object {
 	switch op {
 	case "<":
@@ -615,6 +1239,9 @@ func evalInfixExprInt(op string, left, right object.Int) object.Object {
 		}
 		return object.Int{Value: left.Value / right.Value}
 	case "%":
+		if right.Value == 0 {
+			return errorObj("divide by zero error")
+		}
 		return object.Int{Value: left.Value % right.Value}
 	case "&":
 		return object.Int{Value: left.Value & right.Value}
@@ -622,16 +1249,228 @@ func evalInfixExprInt(op string, left, right object.Int) object.Object {
 		return object.Int{Value: left.Value ^ right.Value}
 	case "|":
 		return object.Int{Value: left.Value | right.Value}
-	case "<<":
-		return object.Int{Value: left.Value << right.Value}
-	case ">>":
-		return object.Int{Value: left.Value >> right.Value}
+	case "<<", ">>":
+		if right.Value < 0 {
+			return errorObj("negative shift count: %d", right.Value)
+		}
+		if op == "<<" {
+			return object.Int{Value: left.Value << uint64(right.Value)}
+		}
+		return object.Int{Value: left.Value >> uint64(right.Value)}
+	default:
+		return errorObj("illegal operator: %s %s %s",
+			object.ObjString(left), op, object.ObjString(right))
+	}
+}
+
+// evalInfixExprSized wraps the result of arithmetic to the width of the
+// operands, dividing and comparing as signed or unsigned depending on type.
+func evalInfixExprSized(op string, left, right object.Sized) object.Object {
+	l, r := left.Value, right.Value
+	signed := left.Signed()
+
+	switch op {
+	case "<", "<=", ">=", ">":
+		var cmp int
+		switch {
+		case signed && left.Int() < right.Int(), !signed && l < r:
+			cmp = -1
+		case l != r:
+			cmp = 1
+		}
+		switch op {
+		case "<":
+			return object.Bool{Value: cmp < 0}
+		case "<=":
+			return object.Bool{Value: cmp <= 0}
+		case ">=":
+			return object.Bool{Value: cmp >= 0}
+		default:
+			return object.Bool{Value: cmp > 0}
+		}
+	case "==":
+		return object.Bool{Value: l == r}
+	case "!=":
+		return object.Bool{Value: l != r}
+	case "+":
+		return object.NewSized(left.Name, l+r)
+	case "-":
+		return object.NewSized(left.Name, l-r)
+	case "*":
+		return object.NewSized(left.Name, l*r)
+	case "/", "%":
+		if r == 0 {
+			return errorObj("divide by zero error")
+		}
+		if signed {
+			if op == "/" {
+				return object.NewSized(left.Name, uint64(left.Int()/right.Int()))
+			}
+			return object.NewSized(left.Name, uint64(left.Int()%right.Int()))
+		}
+		if op == "/" {
+			return object.NewSized(left.Name, l/r)
+		}
+		return object.NewSized(left.Name, l%r)
+	case "&":
+		return object.NewSized(left.Name, l&r)
+	case "^":
+		return object.NewSized(left.Name, l^r)
+	case "|":
+		return object.NewSized(left.Name, l|r)
 	default:
 		return errorObj("illegal operator: %s %s %s",
 			object.ObjString(left), op, object.ObjString(right))
 	}
 }
 
+// evalSized applies an arithmetic, bitwise or shift operator to two
+// fixed-width integers of the same type.
+func evalSized(op string, left, right object.Sized) object.Object {
+	if op == "<<" || op == ">>" {
+		return evalShiftSized(op, left, right)
+	}
+	return evalInfixExprSized(op, left, right)
+}
+
+// evalShiftSized shifts a fixed-width integer by an int or fixed-width count.
+// Right shifts of unsigned values are logical and of signed values arithmetic.
+func evalShiftSized(op string, left, right object.Object) object.Object {
+	var count int64
+	switch right := right.(type) {
+	case object.Int:
+		count = right.Value
+	case object.Sized:
+		count = right.Int()
+	default:
+		return errorObj("illegal shift count: %s", object.ObjString(right))
+	}
+	if count < 0 {
+		return errorObj("negative shift count: %d", count)
+	}
+
+	switch left := left.(type) {
+	case object.Sized:
+		if op == "<<" {
+			return object.NewSized(left.Name, left.Value<<uint64(count))
+		}
+		if left.Signed() {
+			return object.NewSized(left.Name, uint64(left.Int()>>uint64(count)))
+		}
+		return object.NewSized(left.Name, left.Value>>uint64(count))
+	case object.Int:
+		return evalInfixExprInt(op, left, object.Int{Value: count})
+	default:
+		return errorObj("illegal operator: %s %s %s",
+			object.ObjString(left), op, object.ObjString(right))
+	}
+}
+
+func evalInfixExprBig(op string, left, right object.BigInt) object.Object {
+	l, r := left.Value, right.Value
+	result := new(big.Int)
+
+	switch op {
+	case "<":
+		return object.Bool{Value: l.Cmp(r) < 0}
+	case "<=":
+		return object.Bool{Value: l.Cmp(r) <= 0}
+	case "==":
+		return object.Bool{Value: l.Cmp(r) == 0}
+	case "!=":
+		return object.Bool{Value: l.Cmp(r) != 0}
+	case ">=":
+		return object.Bool{Value: l.Cmp(r) >= 0}
+	case ">":
+		return object.Bool{Value: l.Cmp(r) > 0}
+	case "+":
+		result.Add(l, r)
+	case "-":
+		result.Sub(l, r)
+	case "*":
+		result.Mul(l, r)
+	case "/":
+		if r.Sign() == 0 {
+			return errorObj("divide by zero error")
+		}
+		result.Quo(l, r)
+	case "%":
+		if r.Sign() == 0 {
+			return errorObj("divide by zero error")
+		}
+		result.Rem(l, r)
+	case "&":
+		result.And(l, r)
+	case "^":
+		result.Xor(l, r)
+	case "|":
+		result.Or(l, r)
+	default:
+		return errorObj("illegal operator: %s %s %s",
+			object.ObjString(left), op, object.ObjString(right))
+	}
+
+	return object.BigInt{Value: result}
+}
+
+// evalBig applies an arithmetic, bitwise or shift operator to two bigints.
+func evalBig(op string, left, right object.BigInt) object.Object {
+	if op == "<<" || op == ">>" {
+		if !right.Value.IsInt64() {
+			return errorObj("shift count too large: %s", right.Eval())
+		}
+		return evalShiftBig(op, left, object.Int{Value: right.Value.Int64()})
+	}
+	return evalInfixExprBig(op, left, right)
+}
+
+// evalShiftBig shifts a bigint by an int count. Right shifts round towards
+// negative infinity, like an arithmetic shift.
+func evalShiftBig(op string, left object.BigInt, right object.Object) object.Object {
+	var count int64
+	switch right := right.(type) {
+	case object.Int:
+		count = right.Value
+	case object.Sized:
+		count = right.Int()
+	default:
+		return errorObj("illegal shift count: %s", object.ObjString(right))
+	}
+	if count < 0 {
+		return errorObj("negative shift count: %d", count)
+	}
+
+	if op == "<<" {
+		return object.BigInt{Value: new(big.Int).Lsh(left.Value, uint(count))}
+	}
+	return object.BigInt{Value: new(big.Int).Rsh(left.Value, uint(count))}
+}
+
+// coerce converts the value of an integer literal e to the fixed-width integer
+// type typ, such as when a literal is stored in or combined with a u8, or
+// promotes it to a bigint. Values that do not fit are an error; wrapping them
+// needs an explicit cast, as does converting an int that is not a literal.
+func coerce(val object.Object, typ string, e ast.Expression) object.Object {
+	if !ast.IsIntLiteral(e) {
+		return val
+	}
+
+	i, ok := val.(object.Int)
+	if ok && typ == "bigint" {
+		return object.BigInt{Value: big.NewInt(i.Value)}
+	}
+	if !ok || !object.IsSized(typ) {
+		return val
+	}
+
+	sized := object.NewSized(typ, uint64(i.Value))
+	if sized.Int() != i.Value || (!sized.Signed() && i.Value < 0) {
+		return errorObj("%d overflows %s", i.Value, typ)
+	}
+
+	return sized
+}
+
 func evalInfixExprFloat(op string, left, right object.Float) object.Object {
 	switch op {
 	case "<":
@@ -685,55 +1524,305 @@ func evalInfixExprString(op string, left, right object.String) object.Object {
 	}
 }
 
-func evalInfixExprBool(op string, left, right object.Bool) object.Object {
-	switch op {
-	case "==":
-		return object.Bool{Value: left.Value == right.Value}
-	case "!=":
-		return object.Bool{Value: left.Value != right.Value}
-	case "&&":
-		return object.Bool{Value: left.Value && right.Value}
-	case "||":
-		return object.Bool{Value: left.Value || right.Value}
-	default:
-		return errorObj("illegal operator: %s %s %s",
-			object.ObjString(left), op, object.ObjString(right))
+func evalInfixExprBool(op string, left, right object.Bool) object.Object {
+	switch op {
+	case "==":
+		return object.Bool{Value: left.Value == right.Value}
+	case "!=":
+		return object.Bool{Value: left.Value != right.Value}
+	case "&&":
+		return object.Bool{Value: left.Value && right.Value}
+	case "||":
+		return object.Bool{Value: left.Value || right.Value}
+	default:
+		return errorObj("illegal operator: %s %s %s",
+			object.ObjString(left), op, object.ObjString(right))
+	}
+}
+
+func evalTernary(t ast.Ternary, s *object.State) object.Object {
+	cond := Eval(t.Condition, s)
+	if IsError(cond) {
+		return cond
+	}
+
+	if cond.Type() != object.BoolObj {
+		return errorObj("improper ternary condition type: %s",
+			object.ObjString(cond))
+	}
+
+	taken, skipped := t.Consequence, t.Alternative
+	if !cond.(object.Bool).Value {
+		taken, skipped = t.Alternative, t.Consequence
+	}
+
+	val := Eval(taken, s)
+	if IsError(val) {
+		return val
+	}
+
+	other := typeOf(skipped, s)
+	if other != "" && other != object.TypeName(val) {
+		if cond.(object.Bool).Value {
+			return errorObj("mismatched types: %s : %s",
+				object.TypeName(val), other)
+		}
+		return errorObj("mismatched types: %s : %s",
+			other, object.TypeName(val))
+	}
+
+	return val
+}
+
+// evalCast converts a value between int and float, between char and int by
+// code point, or from any value to its printed string. Fixed-width integers
+// convert through int, so casts between widths truncate or sign-extend, and
+// casting a bigint to one keeps its low bits.
+func evalCast(c ast.Cast, s *object.State) object.Object {
+	val := Eval(c.Value, s)
+	if IsError(val) {
+		return val
+	}
+	if val == nil {
+		return errorObj("cannot convert void to %s", c.Type.Value)
+	}
+
+	from, to := object.TypeName(val), c.Type.Value
+	if from == to {
+		return val
+	}
+	if to == "string" {
+		return object.String{Value: val.Eval()}
+	}
+
+	if sized, ok := val.(object.Sized); ok {
+		switch {
+		case to == "float" && !sized.Signed():
+			return object.Float{Value: float64(sized.Value)}
+		case to == "bigint" && !sized.Signed():
+			return object.BigInt{Value: new(big.Int).SetUint64(sized.Value)}
+		}
+		val = object.Int{Value: sized.Int()}
+	}
+
+	if e, ok := val.(object.Enum); ok {
+		val = object.Int{Value: int64(e.Value)}
+	}
+
+	if b, ok := val.(object.BigInt); ok {
+		switch {
+		case to == "float":
+			f, _ := new(big.Float).SetInt(b.Value).Float64()
+			return object.Float{Value: f}
+		case object.IsSized(to):
+			low := new(big.Int).And(b.Value, new(big.Int).SetUint64(math.MaxUint64))
+			return object.NewSized(to, low.Uint64())
+		case !b.Value.IsInt64():
+			return errorObj("bigint out of int range: %s", b.Eval())
+		}
+		val = object.Int{Value: b.Value.Int64()}
+	}
+
+	switch to {
+	case "int":
+		switch val := val.(type) {
+		case object.Int:
+			return val
+		case object.Float:
+			return floatToInt(val)
+		case object.Char:
+			return object.Int{Value: int64(val.Value)}
+		}
+	case "float":
+		if val, ok := val.(object.Int); ok {
+			return object.Float{Value: float64(val.Value)}
+		}
+	case "bigint":
+		switch val := val.(type) {
+		case object.Int:
+			return object.BigInt{Value: big.NewInt(val.Value)}
+		case object.Float:
+			if math.IsNaN(val.Value) || math.IsInf(val.Value, 0) {
+				return errorObj("float out of bigint range: %s", val.Eval())
+			}
+			i, _ := big.NewFloat(val.Value).Int(nil)
+			return object.BigInt{Value: i}
+		case object.Char:
+			return object.BigInt{Value: big.NewInt(int64(val.Value))}
+		}
+	case "char":
+		if val, ok := val.(object.Int); ok {
+			if val.Value > math.MaxInt32 || !utf8.ValidRune(rune(val.Value)) {
+				return errorObj("invalid code point: %d", val.Value)
+			}
+			return object.Char{Value: rune(val.Value)}
+		}
+	default:
+		if object.IsEnumType(to) {
+			return castEnum(val, to, s)
+		}
+		if !object.IsSized(to) {
+			break
+		}
+		switch val := val.(type) {
+		case object.Int:
+			return object.NewSized(to, uint64(val.Value))
+		case object.Float:
+			i := floatToInt(val)
+			if IsError(i) {
+				return i
+			}
+			return object.NewSized(to, uint64(i.(object.Int).Value))
+		case object.Char:
+			return object.NewSized(to, uint64(val.Value))
+		}
+	}
+
+	return errorObj("cannot convert %s to %s", from, to)
+}
+
+// castEnum converts an int to the member of an enum at that position.
+func castEnum(val object.Object, typ string, s *object.State) object.Object {
+	decl, ok := s.Get(typ)
+	if !ok || decl.Type() != object.EnumDeclObj {
+		return errorObj("undeclared type: %s", typ)
+	}
+
+	i, ok := val.(object.Int)
+	if !ok {
+		return errorObj("cannot convert %s to %s", object.TypeName(val), typ)
+	}
+
+	members := decl.(object.EnumDecl).Members
+	if i.Value < 0 || i.Value >= int64(len(members)) {
+		return errorObj("%d is not a member of %s", i.Value, typ)
+	}
+
+	return members[i.Value]
+}
+
+func floatToInt(f object.Float) object.Object {
+	if math.IsNaN(f.Value) || math.IsInf(f.Value, 0) ||
+		f.Value >= math.MaxInt64 || f.Value < math.MinInt64 {
+		return errorObj("float out of int range: %s", f.Eval())
+	}
+	return object.Int{Value: int64(f.Value)}
+}
+
+// typeOf infers the type of an expression without evaluating it, so that the
+// branch a ternary skips can still be checked. An empty string means the type
+// could not be determined.
+func typeOf(e ast.Expression, s *object.State) string {
+	switch e := e.(type) {
+	case ast.CharCon:
+		return "char"
+	case ast.IntCon:
+		return "int"
+	case ast.BigIntCon:
+		return "bigint"
+	case ast.FloatCon:
+		return "float"
+	case ast.StringCon:
+		return "string"
+	case ast.Bool:
+		return "bool"
+	case ast.Identifier:
+		if val, ok := s.Get(e.Name); ok {
+			return object.TypeName(val)
+		}
+	case ast.IndexExpr:
+		left := typeOf(e.Left, s)
+		if _, value, ok := object.MapTypes(left); ok {
+			return value
+		}
+		if strings.HasSuffix(left, "arr") {
+			return strings.TrimSuffix(left, "arr")
+		}
+		if left == "string" {
+			return "char"
+		}
+	case ast.SliceExpr:
+		return "string"
+	case ast.Cast:
+		return e.Type.Value
+	case ast.FieldExpr:
+		if decl, ok := s.Get(typeOf(e.Left, s)); ok && decl.Type() == object.StructDeclObj {
+			for _, field := range decl.(object.StructDecl).Fields {
+				if field.Ident.Name == e.Field.Name {
+					return field.Type.Value
+				}
+			}
+		}
+	case ast.Call:
+		if val, ok := s.Get(e.Function.Name); ok && val.Type() == object.FuncDeclObj {
+			if ret := val.(object.FuncDecl).ReturnType.Value; ret != "void" {
+				return ret
+			}
+		}
+	case ast.PrefixExpr:
+		if e.Op == "!" {
+			return "bool"
+		}
+		return typeOf(e.Right, s)
+	case ast.InfixExpr:
+		switch e.Op {
+		case "<", "<=", "==", "!=", ">=", ">", "&&", "||":
+			return "bool"
+		}
+		left := typeOf(e.Left, s)
+		if left == "char" && e.Op == "+" {
+			return "string"
+		}
+		return left
+	case ast.Ternary:
+		return typeOf(e.Consequence, s)
+	case ast.Lambda:
+		return ast.FuncType(e.Type, e.Parameters).Value
 	}
+	return ""
 }
 
 func evalAssign(a ast.Assign, s *object.State) object.Object {
+	if s.Const(a.Ident.Name) {
+		return errorObj("cannot assign to constant %s", a.Ident.Name)
+	}
+
 	ident := Eval(a.Ident, s)
 	if IsError(ident) {
 		return ident
 	}
 
-	val := Eval(a.Value, s)
+	val := coerce(Eval(a.Value, s), object.TypeName(ident), a.Value)
 	if IsError(val) {
 		return val
 	}
 
-	if ident.Type() != val.Type() {
+	if object.TypeName(ident) != object.TypeName(val) {
 		return errorObj("assignment type mismatch: %s and %s",
-			object.ObjString(ident), object.ObjString(val))
+			object.TypeName(ident), object.TypeName(val))
 	}
 
-	s.Set(a.Ident.Name, val)
+	s.Update(a.Ident.Name, val)
 	return nil
 }
 
 func evalAssignExpr(ae ast.AssignExpr, s *object.State) object.Object {
+	if s.Const(ae.Ident.Name) {
+		return errorObj("cannot assign to constant %s", ae.Ident.Name)
+	}
+
 	ident := Eval(ae.Ident, s)
 	if IsError(ident) {
 		return ident
 	}
 
-	val := Eval(ae.Value, s)
+	self, _ := s.Get(ae.Ident.Name)
+	val := coerce(Eval(ae.Value, s), object.TypeName(self), ae.Value)
 	if IsError(val) {
 		return val
 	}
 
-	self, _ := s.Get(ae.Ident.Name)
-	if self.Type() != val.Type() {
+	if object.ObjString(self) != object.ObjString(val) {
 		return errorObj("mismatched types: %s %s %s",
 			object.ObjString(self), ae.Op, object.ObjString(val))
 	}
@@ -770,6 +1859,10 @@ func evalAssignExpr(ae ast.AssignExpr, s *object.State) object.Object {
 	switch val.Type() {
 	case object.IntObj:
 		newVal = evalInfixExprInt(op, self.(object.Int), val.(object.Int))
+	case object.SizedObj:
+		newVal = evalSized(op, self.(object.Sized), val.(object.Sized))
+	case object.BigIntObj:
+		newVal = evalBig(op, self.(object.BigInt), val.(object.BigInt))
 	case object.FloatObj:
 		newVal = evalInfixExprFloat(op, self.(object.Float), val.(object.Float))
 	case object.StringObj:
@@ -783,183 +1876,441 @@ func evalAssignExpr(ae ast.AssignExpr, s *object.State) object.Object {
 		return newVal
 	}
 
-	s.Set(ae.Ident.Name, newVal)
+	s.Update(ae.Ident.Name, newVal)
 	return nil
 }
 
+func evalIncDecExpr(ide ast.IncDecExpr, s *object.State) object.Object {
+	if s.Const(ide.Ident.Name) {
+		return errorObj("cannot assign to constant %s", ide.Ident.Name)
+	}
+
+	self := Eval(ide.Ident, s)
+	if IsError(self) {
+		return self
+	}
+
+	newVal := evalIncDec(ide.Op, self)
+	if IsError(newVal) {
+		return newVal
+	}
+
+	s.Update(ide.Ident.Name, newVal)
+	if ide.Prefix {
+		return newVal
+	}
+	return self
+}
+
+func evalIncDec(op string, self object.Object) object.Object {
+	var infix string
+
+	switch op {
+	case "++":
+		infix = "+"
+	case "--":
+		infix = "-"
+	default:
+		return errorObj("unknown operator: %s", op)
+	}
+
+	switch self := self.(type) {
+	case object.Int:
+		return evalInfixExprInt(infix, self, object.Int{Value: 1})
+	case object.Sized:
+		return evalInfixExprSized(infix, self, object.NewSized(self.Name, 1))
+	case object.BigInt:
+		return evalInfixExprBig(infix, self, object.BigInt{Value: big.NewInt(1)})
+	case object.Float:
+		return evalInfixExprFloat(infix, self, object.Float{Value: 1.0})
+	default:
+		return errorObj("mismatched types: %s %s= int",
+			object.ObjString(self), infix)
+	}
+}
+
 func evalIndexExpr(ie ast.IndexExpr, s *object.State) object.Object {
-	ident := evalIdent(ie.Ident, s)
-	if IsError(ident) {
-		return ident
+	val, _, err := evalIndex(ie.Left, ie.Index, s)
+	if err != nil {
+		return err
 	}
+	return val
+}
 
-	index := Eval(ie.Index, s)
-	if IsError(index) {
-		return index
+// evalIndex evaluates an indexing expression to the value it refers to and a
+// function that stores a new value in its place. Arrays and maps share their
+// contents, so storing through it updates the container in place. Strings
+// are immutable and have no store function.
+func evalIndex(left, index ast.Expression, s *object.State) (object.Object, func(object.Object), object.Object) {
+	container := Eval(left, s)
+	if IsError(container) {
+		return nil, nil, container
+	}
+
+	key := Eval(index, s)
+	if IsError(key) {
+		return nil, nil, key
+	}
+
+	switch container := container.(type) {
+	case *object.Array:
+		return evalArrayIndex(left, container, key)
+	case object.Map:
+		constant := checkConst(left, s) != nil
+		return evalMapIndex(container, key, index, constant, s)
+	case object.String:
+		return evalStringIndex(left, container, key)
+	default:
+		return nil, nil, errorObj("%s is not an array", ast.ExprString(left))
 	}
-	if index.Type() != object.IntObj {
-		return errorObj("illegal array index: %s", object.ObjString(index))
+}
+
+// evalIndexTarget is evalIndex for the left-hand side of an assignment.
+func evalIndexTarget(left, index ast.Expression, s *object.State) (object.Object, func(object.Object), object.Object) {
+	if err := checkConst(left, s); err != nil {
+		return nil, nil, err
 	}
 
-	array, ok := s.Get(ie.Ident.Name)
-	if !ok {
-		return errorObj("undeclared array: %s", ie.Ident.Name)
+	self, store, err := evalIndex(left, index, s)
+	if err == nil && store == nil {
+		err = errorObj("cannot assign to %s[%s]: strings are immutable",
+			ast.ExprString(left), ast.ExprString(index))
 	}
-	if array.Type() != object.ArrObj {
-		return errorObj("%s is not an array", ie.Ident.Name)
+	return self, store, err
+}
+
+func evalStringIndex(left ast.Expression, str object.String, index object.Object) (object.Object, func(object.Object), object.Object) {
+	if index == nil || index.Type() != object.IntObj {
+		return nil, nil, errorObj("illegal string index: %s",
+			object.ObjString(index))
 	}
 
 	idx := index.(object.Int).Value
-	arr := array.(object.Array).Elements
+	chars := []rune(str.Value)
 
-	if int(idx) < 0 || int(idx) >= len(arr) {
-		return errorObj("array index out of bounds: %s[%d]",
-			ie.Ident.Name, idx)
+	if idx < 0 || idx >= int64(len(chars)) {
+		return nil, nil, errorObj("string index out of bounds: %s[%d]",
+			ast.ExprString(left), idx)
 	}
 
-	return arr[idx]
+	return object.Char{Value: chars[idx]}, nil, nil
 }
 
-func evalAssignIndexExpr(aie ast.AssignIndexExpr, s *object.State) object.Object {
-	ident := evalIdent(aie.Ident, s)
-	if IsError(ident) {
-		return ident
+// evalSliceExpr evaluates s[a:b] to the characters of s from a up to but not
+// including b. A missing bound defaults to the start or end of the string.
+func evalSliceExpr(se ast.SliceExpr, s *object.State) object.Object {
+	left := Eval(se.Left, s)
+	if IsError(left) {
+		return left
 	}
 
-	index := Eval(aie.Index, s)
-	if IsError(index) {
-		return index
+	str, ok := left.(object.String)
+	if !ok {
+		return errorObj("cannot slice %s", object.ObjString(left))
 	}
-	if index.Type() != object.IntObj {
-		return errorObj("illegal array index: %s", object.ObjString(index))
+	chars := []rune(str.Value)
+
+	bound := func(e ast.Expression, def int64) (int64, object.Object) {
+		if e == nil {
+			return def, nil
+		}
+		val := Eval(e, s)
+		if IsError(val) {
+			return 0, val
+		}
+		if val == nil || val.Type() != object.IntObj {
+			return 0, errorObj("illegal string index: %s",
+				object.ObjString(val))
+		}
+		return val.(object.Int).Value, nil
 	}
 
-	array, ok := s.Get(aie.Ident.Name)
-	if !ok {
-		return errorObj("undeclared array: %s", aie.Ident.Name)
+	low, err := bound(se.Low, 0)
+	if err != nil {
+		return err
 	}
-	if array.Type() != object.ArrObj {
-		return errorObj("%s is not an array", aie.Ident.Name)
+	high, err := bound(se.High, int64(len(chars)))
+	if err != nil {
+		return err
+	}
+
+	if low < 0 || high > int64(len(chars)) || low > high {
+		return errorObj("string slice out of bounds: %s[%d:%d]",
+			ast.ExprString(se.Left), low, high)
+	}
+
+	return object.String{Value: string(chars[low:high])}
+}
+
+func evalArrayIndex(left ast.Expression, array *object.Array, index object.Object) (object.Object, func(object.Object), object.Object) {
+	if index == nil || index.Type() != object.IntObj {
+		return nil, nil, errorObj("illegal array index: %s",
+			object.ObjString(index))
 	}
 
 	idx := index.(object.Int).Value
-	arr := array.(object.Array).Elements
+	if idx < 0 || idx >= int64(array.Len()) {
+		return nil, nil, errorObj("array index out of bounds: %s[%d]",
+			ast.ExprString(left), idx)
+	}
+
+	return array.Elements[idx], func(val object.Object) {
+		array.Elements[idx] = val
+	}, nil
+}
+
+// evalMapIndex looks up key in m. A key that is not present refers to the
+// zero value of the map's value type until something is stored under it.
+// constant is set if m belongs to a constant, which reading must not change.
+func evalMapIndex(m object.Map, key object.Object, index ast.Expression, constant bool, s *object.State) (object.Object, func(object.Object), object.Object) {
+	if key = coerce(key, m.KeyType, index); IsError(key) {
+		return nil, nil, key
+	}
+	if object.TypeName(key) != m.KeyType {
+		return nil, nil, errorObj("illegal map key: %s",
+			object.TypeName(key))
+	}
+
+	val, ok := m.Get(key)
+	if !ok {
+		val = zeroValue(m.ValueType, s)
+		if IsError(val) {
+			return nil, nil, val
+		}
+		// Nested containers are stored on first access so that writes
+		// through them, e.g. m[i][j] = x or push(m[i], x), are not lost.
+		// Such writes to a constant are rejected, so it is left alone.
+		switch val.Type() {
+		case object.ArrObj, object.MapObj, object.StructObj:
+			if !constant {
+				m.Set(key, val)
+			}
+		}
+	}
 
-	if int(idx) < 0 || int(idx) >= len(arr) {
-		return errorObj("array index out of bounds: %s[%d]",
-			aie.Ident.Name, idx)
+	return val, func(val object.Object) { m.Set(key, val) }, nil
+}
+
+func evalAssignIndexExpr(aie ast.AssignIndexExpr, s *object.State) object.Object {
+	self, store, err := evalIndexTarget(aie.Left, aie.Index, s)
+	if err != nil {
+		return err
 	}
 
-	val := Eval(aie.Value, s)
+	val := coerce(Eval(aie.Value, s), object.TypeName(self), aie.Value)
 	if IsError(val) {
 		return val
 	}
 
-	if arr[idx].Type() != val.Type() {
+	if object.TypeName(self) != object.TypeName(val) {
 		return errorObj("assignment type mismatch: %s and %s",
-			object.ObjString(arr[idx]), object.ObjString(val))
+			object.TypeName(self), object.TypeName(val))
 	}
 
-	arr[idx] = val
-	elementType := array.(object.Array).ElementType
-	s.Set(aie.Ident.Name, object.Array{ElementType: elementType, Elements: arr})
+	store(val)
 	return nil
 }
 
 func evalAssignExprIndexExpr(aeie ast.AssignExprIndexExpr, s *object.State) object.Object {
-	ident := evalIdent(aeie.Ident, s)
-	if IsError(ident) {
-		return ident
+	self, store, err := evalIndexTarget(aeie.Left, aeie.Index, s)
+	if err != nil {
+		return err
 	}
 
-	index := Eval(aeie.Index, s)
-	if IsError(index) {
-		return index
-	}
-	if index.Type() != object.IntObj {
-		return errorObj("illegal array index: %s", object.ObjString(index))
+	val := Eval(aeie.Value, s)
+	if IsError(val) {
+		return val
 	}
 
-	array, ok := s.Get(aeie.Ident.Name)
-	if !ok {
-		return errorObj("undeclared array: %s", aeie.Ident.Name)
-	}
-	if array.Type() != object.ArrObj {
-		return errorObj("%s is not an array", aeie.Ident.Name)
+	newVal := evalCompound(aeie.Op, self, val, aeie.Value)
+	if IsError(newVal) {
+		return newVal
 	}
 
-	idx := index.(object.Int).Value
-	arr := array.(object.Array).Elements
-
-	if int(idx) < 0 || int(idx) >= len(arr) {
-		return errorObj("array index out of bounds: %s[%d]",
-			aeie.Ident.Name, idx)
-	}
+	store(newVal)
+	return nil
+}
 
-	val := Eval(aeie.Value, s)
-	if IsError(val) {
+// evalCompound applies an assignment operator such as "+=" to the current
+// value of an array element or struct field, val being the value of e.
+func evalCompound(op string, self, val object.Object, e ast.Expression) object.Object {
+	if val = coerce(val, object.TypeName(self), e); IsError(val) {
 		return val
 	}
 
-	if arr[idx].Type() != val.Type() {
+	if object.TypeName(self) != object.TypeName(val) {
 		return errorObj("assignment type mismatch: %s and %s",
-			object.ObjString(arr[idx]), object.ObjString(val))
+			object.TypeName(self), object.TypeName(val))
 	}
 
-	var op string
-	var newVal object.Object
+	var infix string
 
-	switch aeie.Op {
+	switch op {
 	case "=":
-		op = "="
+		return val
 	case "+=":
-		op = "+"
+		infix = "+"
 	case "-=":
-		op = "-"
+		infix = "-"
 	case "*=":
-		op = "*"
+		infix = "*"
 	case "/=":
-		op = "/"
+		infix = "/"
 	case "%=":
-		op = "%"
+		infix = "%"
 	case "&=":
-		op = "&"
+		infix = "&"
 	case "^=":
-		op = "^"
+		infix = "^"
 	case "|=":
-		op = "|"
+		infix = "|"
 	case "<<=":
-		op = "<<"
+		infix = "<<"
 	case ">>=":
-		op = ">>"
+		infix = ">>"
 	default:
 		return errorObj("illegal operator: %s %s %s",
-			object.ObjString(arr[idx]), aeie.Op, object.ObjString(val))
+			object.ObjString(self), op, object.ObjString(val))
 	}
 
-	if op == "=" {
-		newVal = val
-	} else {
-		switch val.Type() {
-		case object.IntObj:
-			newVal = evalInfixExprInt(op, arr[idx].(object.Int), val.(object.Int))
-		case object.FloatObj:
-			newVal = evalInfixExprFloat(op, arr[idx].(object.Float), val.(object.Float))
-		case object.StringObj:
-			newVal = evalInfixExprString(op, arr[idx].(object.String), val.(object.String))
+	switch val.Type() {
+	case object.IntObj:
+		return evalInfixExprInt(infix, self.(object.Int), val.(object.Int))
+	case object.SizedObj:
+		return evalSized(infix, self.(object.Sized), val.(object.Sized))
+	case object.BigIntObj:
+		return evalBig(infix, self.(object.BigInt), val.(object.BigInt))
+	case object.FloatObj:
+		return evalInfixExprFloat(infix, self.(object.Float), val.(object.Float))
+	case object.StringObj:
+		return evalInfixExprString(infix, self.(object.String), val.(object.String))
+	default:
+		return errorObj("illegal assignment: %s %s %s",
+			object.ObjString(self), op, object.ObjString(val))
+	}
+}
+
+func evalIncDecIndexExpr(idie ast.IncDecIndexExpr, s *object.State) object.Object {
+	self, store, err := evalIndexTarget(idie.Left, idie.Index, s)
+	if err != nil {
+		return err
+	}
+
+	newVal := evalIncDec(idie.Op, self)
+	if IsError(newVal) {
+		return newVal
+	}
+
+	store(newVal)
+	if idie.Prefix {
+		return newVal
+	}
+	return self
+}
+
+func evalFieldExpr(fe ast.FieldExpr, s *object.State) object.Object {
+	fields, err := evalField(fe.Left, fe.Field, s)
+	if err != nil {
+		return err
+	}
+	return fields[fe.Field.Name]
+}
+
+// checkConst rejects assignments through an element or field of a constant,
+// such as xs[0] = 1 or p.x += 1, and built-in functions that modify one.
+func checkConst(left ast.Expression, s *object.State) object.Object {
+	for {
+		switch e := left.(type) {
+		case ast.IndexExpr:
+			left = e.Left
+		case ast.FieldExpr:
+			left = e.Left
+		case ast.Identifier:
+			if s.Const(e.Name) {
+				return errorObj("cannot assign to constant %s", e.Name)
+			}
+			return nil
 		default:
-			return errorObj("illegal assignment: %s %s %s",
-				object.ObjString(arr[idx]), aeie.Op, object.ObjString(val))
+			return nil
 		}
 	}
+}
+
+// evalField evaluates the struct of a field access and checks that it has the
+// named field. Structs share their fields, so writing through the returned map
+// updates the struct in place.
+func evalField(left ast.Expression, field ast.Identifier, s *object.State) (map[string]object.Object, object.Object) {
+	val := Eval(left, s)
+	if IsError(val) {
+		return nil, val
+	}
+
+	st, ok := val.(object.Struct)
+	if !ok {
+		return nil, errorObj("%s is not a struct", ast.ExprString(left))
+	}
+
+	if _, ok := st.Values[field.Name]; !ok {
+		return nil, errorObj("struct %s has no field %s", st.Name, field.Name)
+	}
 
-	arr[idx] = newVal
-	elementType := array.(object.Array).ElementType
-	s.Set(aeie.Ident.Name, object.Array{ElementType: elementType, Elements: arr})
+	return st.Values, nil
+}
+
+func evalAssignExprFieldExpr(aefe ast.AssignExprFieldExpr, s *object.State) object.Object {
+	if err := checkConst(aefe.Left, s); err != nil {
+		return err
+	}
+
+	fields, err := evalField(aefe.Left, aefe.Field, s)
+	if err != nil {
+		return err
+	}
+
+	val := Eval(aefe.Value, s)
+	if IsError(val) {
+		return val
+	}
+
+	newVal := evalCompound(aefe.Op, fields[aefe.Field.Name], val, aefe.Value)
+	if IsError(newVal) {
+		return newVal
+	}
+
+	fields[aefe.Field.Name] = newVal
 	return nil
 }
 
+func evalIncDecFieldExpr(idfe ast.IncDecFieldExpr, s *object.State) object.Object {
+	if err := checkConst(idfe.Left, s); err != nil {
+		return err
+	}
+
+	fields, err := evalField(idfe.Left, idfe.Field, s)
+	if err != nil {
+		return err
+	}
+
+	self := fields[idfe.Field.Name]
+	newVal := evalIncDec(idfe.Op, self)
+	if IsError(newVal) {
+		return newVal
+	}
+
+	fields[idfe.Field.Name] = newVal
+	if idfe.Prefix {
+		return newVal
+	}
+	return self
+}
+
 func evalCall(c ast.Call, s *object.State) object.Object {
+	name := c.Function.Name
+	if _, ok := s.Get(name); !ok && !IsBuiltin(name) {
+		return errorObj("identifier %s undeclared%s",
+			name, misc.DidYouMean(name, functionNames(s)))
+	}
+
 	function := Eval(c.Function, s)
 	if IsError(function) {
 		return function
@@ -968,8 +2319,8 @@ func evalCall(c ast.Call, s *object.State) object.Object {
 	isBuiltin := function.Type() == object.BuiltInObj
 	isFunction := function.Type() == object.FuncDeclObj
 	if !isBuiltin && !isFunction {
-		return errorObj("%s is not a declared or built-in function",
-			c.Function.Name)
+		return errorObj("%s is not a declared or built-in function%s",
+			name, misc.DidYouMean(name, functionNames(s)))
 	}
 
 	args := evalExpressions(c.Arguments, s)
@@ -989,65 +2340,141 @@ func evalCall(c ast.Call, s *object.State) object.Object {
 		}
 
 		for i := 0; i < len(params); i++ {
+			expected := params[i].Type.Value
 			if params[i].Array {
-				if args[i].Type() != object.ArrObj {
+				if args[i] == nil || args[i].Type() != object.ArrObj {
 					return errorObj("passed non-array as array parameter")
 				}
-				elementType := args[i].(object.Array).ElementType
-				if elementType != params[i].Type.Value {
-					return errorObj("mismatched types for argument %d", i+1)
-				}
-			} else {
-				switch args[i].Type() {
-				case object.IntObj:
-					if params[i].Type.Value != "int" {
-						return errorObj("mismatched types for argument %d", i+1)
-					}
-				case object.FloatObj:
-					if params[i].Type.Value != "float" {
-						return errorObj("mismatched types for argument %d", i+1)
-					}
-				case object.StringObj:
-					if params[i].Type.Value != "string" {
-						return errorObj("mismatched types for argument %d", i+1)
-					}
-				case object.BoolObj:
-					if params[i].Type.Value != "bool" {
-						return errorObj("mismatched types for argument %d", i+1)
-					}
-				default:
-					return errorObj("illegal type for argument %d", i)
-				}
+				expected += "arr"
+			}
+			if args[i] = coerce(args[i], expected, c.Arguments[i]); IsError(args[i]) {
+				return args[i]
+			}
+			if object.TypeName(args[i]) != expected {
+				return errorObj("mismatched types for argument %d", i+1)
 			}
 		}
 	}
 
 	switch function := function.(type) {
 	case object.FuncDecl:
-		callState := object.NewState()
+		if function.State == nil {
+			return errorObj("%s is an uninitialized function",
+				c.Function.Name)
+		}
+
+		callState := object.NewFrameState(function.State)
 		for i, param := range function.Parameters {
 			callState.Set(param.Ident.Name, args[i])
 		}
-		object.CopyFunctions(callState, s)
 
 		evaluated := Eval(function.Body, callState)
-		if evaluated != nil && evaluated.Type() == object.ReturnObj {
-			return evaluated.(object.Return).Value
+		switch evaluated := evaluated.(type) {
+		case object.Return:
+			return checkReturn(c.Function.Name, function.ReturnType,
+				evaluated.Value, evaluated.Expr)
+		case object.Break, object.Continue:
+			return loopControlError(evaluated)
+		case object.Error:
+			evaluated.Trace = append(evaluated.Trace, object.Frame{
+				Function:  c.Function.Name,
+				Arguments: args,
+				Pos:       c.Pos,
+			})
+			return evaluated
 		}
-		return evaluated
+		if function.ReturnType.Value != "void" {
+			return errorObj("missing return in %s()", c.Function.Name)
+		}
+		return nil
 	case object.BuiltIn:
+		if function.Mutates && len(c.Arguments) > 0 {
+			if err := checkConst(c.Arguments[0], s); err != nil {
+				return err
+			}
+		}
+		if i := function.Element; i > 0 && i < len(args) {
+			if args[i] = coerceElement(args[0], args[i], c.Arguments[i]); IsError(args[i]) {
+				return args[i]
+			}
+		}
 		return function.Function(args...)
 	default:
 		return errorObj("not a function: %s", c.Function.Name)
 	}
 }
 
+func checkReturn(name string, ret ast.Type, val object.Object, e ast.Expression) object.Object {
+	if ret.Value == "void" {
+		if val != nil {
+			return errorObj("void function %s() returned %s",
+				name, object.TypeName(val))
+		}
+		return nil
+	}
+
+	if val == nil {
+		return errorObj("missing return value in %s()", name)
+	}
+
+	if len(ret.Elements) > 0 {
+		tuple, ok := val.(object.Tuple)
+		if !ok {
+			return errorObj("mismatched return type: %s %s() returned %s",
+				ret.Value, name, object.TypeName(val))
+		}
+		if len(tuple.Elements) != len(ret.Elements) {
+			return errorObj("%s() returns %d values, not %d",
+				name, len(ret.Elements), len(tuple.Elements))
+		}
+		elements := make([]object.Object, len(tuple.Elements))
+		for i, element := range tuple.Elements {
+			if elements[i] = coerce(element, ret.Elements[i].Value, ast.ElementOf(e, i)); IsError(elements[i]) {
+				return elements[i]
+			}
+		}
+		val = object.Tuple{Elements: elements}
+	} else if val = coerce(val, ret.Value, e); IsError(val) {
+		return val
+	}
+
+	if object.TypeName(val) != ret.Value {
+		return errorObj("mismatched return type: %s %s() returned %s",
+			ret.Value, name, object.TypeName(val))
+	}
+
+	return val
+}
+
+func evalTuple(t ast.Tuple, s *object.State) object.Object {
+	elements := evalExpressions(t.Elements, s)
+	if len(elements) == 1 && IsError(elements[0]) {
+		return elements[0]
+	}
+	for i, element := range elements {
+		if element == nil {
+			return errorObj("void value in position %d of %s",
+				i+1, ast.ExprString(t))
+		}
+	}
+	return object.Tuple{Elements: elements}
+}
+
+func evalLambda(l ast.Lambda, s *object.State) object.Object {
+	return object.FuncDecl{
+		ReturnType: l.Type,
+		Parameters: l.Parameters,
+		Body:       l.Body,
+		State:      s,
+	}
+}
+
 func evalArray(a ast.Array, s *object.State) object.Object {
 	elements := evalExpressions(a.Elements, s)
 	if len(elements) == 1 && IsError(elements[0]) {
 		return elements[0]
 	}
-	return object.Array{Elements: elements}
+	return &object.Array{Elements: elements}
 }
 
 func evalExpressions(args []ast.Expression, s *object.State) []object.Object {
@@ -1070,6 +2497,14 @@ func evalIntCon(ic ast.IntCon) object.Object {
 	return object.Int{Value: ic.Value}
 }
 
+func evalBigIntCon(bc ast.BigIntCon) object.Object {
+	val, ok := new(big.Int).SetString(bc.Value, 10)
+	if !ok {
+		return errorObj("invalid integer literal: %s", bc.Value)
+	}
+	return object.BigInt{Value: val}
+}
+
 func evalFloatCon(fc ast.FloatCon) object.Object {
 	return object.Float{Value: fc.Value}
 }
@@ -1091,5 +2526,18 @@ func evalIdent(i ast.Identifier, s *object.State) object.Object {
 		return function
 	}
 
-	return errorObj("identifier %s undeclared", i.Name)
+	names := append(s.Names(), BuiltinNames()...)
+	return errorObj("identifier %s undeclared%s",
+		i.Name, misc.DidYouMean(i.Name, names))
+}
+
+// functionNames lists the functions that can be called from s.
+func functionNames(s *object.State) []string {
+	names := BuiltinNames()
+	for _, name := range s.Names() {
+		if val, _ := s.Get(name); val != nil && val.Type() == object.FuncDeclObj {
+			names = append(names, name)
+		}
+	}
+	return names
 }
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"ariel/check"
 	"ariel/eval"
 	"ariel/object"
 	"ariel/parser"
@@ -29,7 +30,15 @@ func main() {
 			os.Exit(1)
 		}
 
-		program := parser.ParseProgram(infile, *debug)
+		program, errs := parser.ParseProgram(infile, *debug)
+		if len(errs) == 0 {
+			errs = check.Check(program)
+		}
+		if len(errs) > 0 {
+			fmt.Println(object.Errors(errs))
+			os.Exit(1)
+		}
+
 		state := object.NewState()
 		result := eval.Eval(program, state)
 		if eval.IsError(result) {
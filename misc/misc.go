@@ -1,6 +1,9 @@
 package misc
 
-import "bytes"
+import (
+	"bytes"
+	"fmt"
+)
 
 func Flounder(msg string) string {
 	var out bytes.Buffer
@@ -16,3 +19,60 @@ func Flounder(msg string) string {
 	out.WriteString("        `\"\"```")
 	return out.String()
 }
+
+// DidYouMean suggests the candidate closest to a misspelled name, for the end
+// of an error message, or returns "" if none is close enough to be likely.
+func DidYouMean(name string, candidates []string) string {
+	best, bestDist := "", len([]rune(name))/3+1
+	for _, candidate := range candidates {
+		dist := distance(name, candidate)
+		if dist == 0 {
+			continue
+		}
+		if dist < bestDist || dist == bestDist && best != "" && candidate < best {
+			best, bestDist = candidate, dist
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", best)
+}
+
+// distance is the edit distance between a and b: the fewest insertions,
+// deletions, substitutions and swaps of adjacent characters turning one into
+// the other.
+func distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = d[i-1][j-1] + cost
+			if d[i-1][j]+1 < d[i][j] {
+				d[i][j] = d[i-1][j] + 1
+			}
+			if d[i][j-1]+1 < d[i][j] {
+				d[i][j] = d[i][j-1] + 1
+			}
+			swapped := i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1]
+			if swapped && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
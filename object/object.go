@@ -13,8 +13,14 @@ package object
 
 import (
 	"ariel/ast"
+	"ariel/color"
+	"ariel/misc"
 	"bytes"
 	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type ObjectType int
@@ -28,33 +34,63 @@ const (
 	ErrorObj ObjectType = iota
 	CharObj
 	IntObj
+	SizedObj
+	BigIntObj
 	FloatObj
 	StringObj
 	BoolObj
 	ArrObj
+	StructObj
+	EnumObj
+	MapObj
+	TupleObj
 	ReturnObj
+	BreakObj
+	ContinueObj
 	FuncDeclObj
+	StructDeclObj
+	EnumDeclObj
 	BuiltInObj
 )
 
 func ObjString(obj Object) string {
-	switch obj.(type) {
+	switch obj := obj.(type) {
 	case Char:
 		return "char"
 	case Int:
 		return "int"
+	case Sized:
+		return obj.Name
+	case BigInt:
+		return "bigint"
 	case Float:
 		return "float"
 	case String:
 		return "string"
 	case Bool:
 		return "bool"
-	case Array:
+	case *Array:
 		return "array"
+	case Struct:
+		return "struct"
+	case Enum:
+		return "enum"
+	case Map:
+		return "map"
+	case Tuple:
+		return "tuple"
 	case Return:
 		return "return"
+	case Break:
+		return "break"
+	case Continue:
+		return "continue"
 	case FuncDecl:
 		return "funcdecl"
+	case StructDecl:
+		return "structdecl"
+	case EnumDecl:
+		return "enumdecl"
 	case BuiltIn:
 		return "builtin"
 	default:
@@ -62,19 +98,122 @@ func ObjString(obj Object) string {
 	}
 }
 
+// TypeName is like ObjString, but spells out the element type of an array and
+// the signature of a function so that they can be compared against a declared
+// type.
+func TypeName(obj Object) string {
+	switch obj := obj.(type) {
+	case *Array:
+		if obj.ElementType != "" {
+			return obj.ElementType + "arr"
+		}
+	case Struct:
+		return "struct " + obj.Name
+	case Enum:
+		return "enum " + obj.Name
+	case Map:
+		return "map<" + obj.KeyType + "," + obj.ValueType + ">"
+	case Tuple:
+		types := make([]string, len(obj.Elements))
+		for i, element := range obj.Elements {
+			types[i] = TypeName(element)
+		}
+		return "(" + strings.Join(types, ",") + ")"
+	case FuncDecl:
+		return ast.FuncType(obj.ReturnType, obj.Parameters).Value
+	}
+	return ObjString(obj)
+}
+
+// Error is a runtime error, located at the node whose evaluation raised it.
+// Its trace lists the calls it passed up through, innermost first.
 type Error struct {
 	Message string
+	Pos     ast.Pos
+	Trace   []Frame
 }
 
 func (e Error) Type() ObjectType { return ErrorObj }
-func (e Error) Eval() string     { return e.Message }
+
+func (e Error) Eval() string { return Errors([]error{e}) }
+
+// Error prefixes the message with its position, once known, as in
+// "tests/heapsort.arl:12:9: error: ...", and follows it with the trace.
+func (e Error) Error() string {
+	msg := "error: " + e.Message
+	if e.Pos.Line != 0 {
+		msg = e.Pos.String() + ": " + msg
+	}
+
+	for i, frame := range e.Trace {
+		if i == maxFrames {
+			msg += "\n...additional frames elided..."
+			break
+		}
+		msg += "\n" + frame.String()
+	}
+
+	return msg
+}
+
+// Errors renders a list of errors, one after another, the way Eval renders
+// a single one.
+func Errors(errs []error) string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	msg := strings.ReplaceAll(strings.Join(msgs, "\n"), "\n", "\"\n\"")
+	return color.Red + misc.Flounder(msg) + color.Reset
+}
+
+// maxFrames bounds the frames printed for an error, so that runaway
+// recursion does not bury the message.
+const maxFrames = 20
+
+// Frame is a call of a function that was in progress when an error occurred.
+type Frame struct {
+	Function  string
+	Arguments []Object
+	Pos       ast.Pos
+}
+
+// String renders the call as it might have been written, with the position
+// it was called from.
+func (f Frame) String() string {
+	args := make([]string, len(f.Arguments))
+	for i, arg := range f.Arguments {
+		args[i] = argString(arg)
+	}
+	return fmt.Sprintf("%s(%s)\n    called at %s",
+		f.Function, strings.Join(args, ", "), f.Pos)
+}
+
+// argString renders an argument in a frame, quoting strings and chars and
+// shortening values too long to read at a glance.
+func argString(arg Object) string {
+	var str string
+	switch arg := arg.(type) {
+	case String:
+		str = strconv.Quote(arg.Value)
+	case Char:
+		str = strconv.QuoteRune(arg.Value)
+	default:
+		str = arg.Eval()
+	}
+
+	if runes := []rune(str); len(runes) > 32 {
+		return string(runes[:29]) + "..."
+	}
+	return str
+}
 
 type Char struct {
-	Value string
+	Value rune
 }
 
 func (c Char) Type() ObjectType { return CharObj }
-func (c Char) Eval() string     { return c.Value }
+func (c Char) Eval() string     { return string(c.Value) }
 
 type Int struct {
 	Value int64
@@ -83,6 +222,78 @@ type Int struct {
 func (i Int) Type() ObjectType { return IntObj }
 func (i Int) Eval() string     { return fmt.Sprintf("%d", i.Value) }
 
+// Sized is a fixed-width integer such as u8 or i32. Value holds the bits of
+// the integer, truncated to the width of its type, so arithmetic on it wraps
+// around the same way it does in C.
+type Sized struct {
+	Name  string
+	Value uint64
+}
+
+var widths = map[string]uint{
+	"i8": 8, "i16": 16, "i32": 32, "i64": 64,
+	"u8": 8, "u16": 16, "u32": 32, "u64": 64,
+}
+
+// IsSized reports whether name is one of the fixed-width integer types.
+func IsSized(name string) bool {
+	_, ok := widths[name]
+	return ok
+}
+
+// IsInteger reports whether name is one of the integer types that a plain int
+// literal converts to implicitly.
+func IsInteger(name string) bool {
+	return IsSized(name) || name == "bigint"
+}
+
+// IsStructType reports whether name is a struct type, not an array of one.
+func IsStructType(name string) bool {
+	return strings.HasPrefix(name, "struct ") && !strings.HasSuffix(name, "arr")
+}
+
+// IsEnumType reports whether name is an enum type, not an array of one.
+func IsEnumType(name string) bool {
+	return strings.HasPrefix(name, "enum ") && !strings.HasSuffix(name, "arr")
+}
+
+// NewSized truncates bits to the width of the named type.
+func NewSized(name string, bits uint64) Sized {
+	if width := widths[name]; width < 64 {
+		bits &= 1<<width - 1
+	}
+	return Sized{Name: name, Value: bits}
+}
+
+func (s Sized) Type() ObjectType { return SizedObj }
+func (s Sized) Eval() string {
+	if s.Signed() {
+		return fmt.Sprintf("%d", s.Int())
+	}
+	return fmt.Sprintf("%d", s.Value)
+}
+
+func (s Sized) Signed() bool { return s.Name[0] == 'i' }
+
+// Int sign-extends signed values to 64 bits. Unsigned values are returned as
+// is, so a u64 above the int64 range comes back negative.
+func (s Sized) Int() int64 {
+	if width := widths[s.Name]; s.Signed() && width < 64 {
+		shift := 64 - width
+		return int64(s.Value<<shift) >> shift
+	}
+	return int64(s.Value)
+}
+
+// BigInt is an arbitrary-precision integer. Operations on it always allocate
+// a new big.Int, so values can be shared the same way ints are.
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b BigInt) Type() ObjectType { return BigIntObj }
+func (b BigInt) Eval() string     { return b.Value.String() }
+
 type Float struct {
 	Value float64
 }
@@ -109,8 +320,8 @@ type Array struct {
 	Elements    []Object
 }
 
-func (a Array) Type() ObjectType { return ArrObj }
-func (a Array) Eval() string {
+func (a *Array) Type() ObjectType { return ArrObj }
+func (a *Array) Eval() string {
 	var out bytes.Buffer
 	if len(a.Elements) > 0 {
 		out.WriteString("{ ")
@@ -127,12 +338,207 @@ func (a Array) Eval() string {
 	return out.String()
 }
 
+func (a *Array) Len() int { return len(a.Elements) }
+
+func (a *Array) Push(val Object) {
+	a.Elements = append(a.Elements, val)
+}
+
+func (a *Array) Pop() Object {
+	last := a.Elements[len(a.Elements)-1]
+	a.Elements = a.Elements[:len(a.Elements)-1]
+	return last
+}
+
+func (a *Array) Insert(i int, val Object) {
+	a.Elements = append(a.Elements, nil)
+	copy(a.Elements[i+1:], a.Elements[i:])
+	a.Elements[i] = val
+}
+
+func (a *Array) Remove(i int) Object {
+	removed := a.Elements[i]
+	a.Elements = append(a.Elements[:i], a.Elements[i+1:]...)
+	return removed
+}
+
+// Struct values share their fields the same way arrays share their elements,
+// so a struct updated through a copy or a parameter is updated everywhere.
+type Struct struct {
+	Name   string
+	Fields []string
+	Values map[string]Object
+}
+
+func (s Struct) Type() ObjectType { return StructObj }
+func (s Struct) Eval() string {
+	var out bytes.Buffer
+	if len(s.Fields) > 0 {
+		out.WriteString("{ ")
+		for i, field := range s.Fields {
+			out.WriteString(field + ": " + s.Values[field].Eval())
+			if i+1 != len(s.Fields) {
+				out.WriteString(", ")
+			}
+		}
+		out.WriteString(" }")
+	} else {
+		out.WriteString("{}")
+	}
+	return out.String()
+}
+
+// MapTypes splits a map type such as "map<string,intarr>" into its key and
+// value types.
+func MapTypes(typ string) (string, string, bool) {
+	if !strings.HasPrefix(typ, "map<") || !strings.HasSuffix(typ, ">") {
+		return "", "", false
+	}
+
+	inner := typ[len("map<") : len(typ)-1]
+	depth := 0
+	for i, c := range inner {
+		switch c {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				return inner[:i], inner[i+1:], true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// Maps are keyed by the printed form of their keys, which is unique among
+// keys of a single type. Like arrays, maps share their contents.
+type Map struct {
+	KeyType   string
+	ValueType string
+	Keys      map[string]Object
+	Values    map[string]Object
+}
+
+func NewMap(keyType, valueType string) Map {
+	return Map{
+		KeyType:   keyType,
+		ValueType: valueType,
+		Keys:      make(map[string]Object),
+		Values:    make(map[string]Object),
+	}
+}
+
+func (m Map) Type() ObjectType { return MapObj }
+func (m Map) Eval() string {
+	var out bytes.Buffer
+	keys := m.SortedKeys()
+	if len(keys) > 0 {
+		out.WriteString("{ ")
+		for i, key := range keys {
+			out.WriteString(key.Eval() + ": " + m.Values[key.Eval()].Eval())
+			if i+1 != len(keys) {
+				out.WriteString(", ")
+			}
+		}
+		out.WriteString(" }")
+	} else {
+		out.WriteString("{}")
+	}
+	return out.String()
+}
+
+func (m Map) Get(key Object) (Object, bool) {
+	val, ok := m.Values[key.Eval()]
+	return val, ok
+}
+
+func (m Map) Set(key Object, val Object) {
+	m.Keys[key.Eval()] = key
+	m.Values[key.Eval()] = val
+}
+
+func (m Map) Delete(key Object) {
+	delete(m.Keys, key.Eval())
+	delete(m.Values, key.Eval())
+}
+
+// SortedKeys returns the keys of m in ascending order, so that maps print and
+// iterate the same way every time.
+func (m Map) SortedKeys() []Object {
+	keys := make([]Object, 0, len(m.Keys))
+	for _, key := range m.Keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		switch key := keys[i].(type) {
+		case Int:
+			return key.Value < keys[j].(Int).Value
+		case Sized:
+			if key.Signed() {
+				return key.Int() < keys[j].(Sized).Int()
+			}
+			return key.Value < keys[j].(Sized).Value
+		case BigInt:
+			return key.Value.Cmp(keys[j].(BigInt).Value) < 0
+		case Enum:
+			return key.Value < keys[j].(Enum).Value
+		case Bool:
+			return !key.Value && keys[j].(Bool).Value
+		default:
+			return key.Eval() < keys[j].Eval()
+		}
+	})
+	return keys
+}
+
+// Tuple holds the values returned together by a function such as
+// (int, int) divmod(int a, int b).
+type Tuple struct {
+	Elements []Object
+}
+
+func (t Tuple) Type() ObjectType { return TupleObj }
+func (t Tuple) Eval() string {
+	values := make([]string, len(t.Elements))
+	for i, element := range t.Elements {
+		values[i] = element.Eval()
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+// Return carries a returned value, and the expression it came from, out of
+// a function's body.
 type Return struct {
 	Value Object
+	Expr  ast.Expression
 }
 
 func (r Return) Type() ObjectType { return ReturnObj }
-func (r Return) Eval() string     { return r.Value.Eval() }
+func (r Return) Eval() string {
+	if r.Value == nil {
+		return ""
+	}
+	return r.Value.Eval()
+}
+
+type Break struct {
+	Label string
+	Pos   ast.Pos
+}
+
+func (b Break) Type() ObjectType { return BreakObj }
+func (b Break) Eval() string     { return "break" }
+
+type Continue struct {
+	Label string
+	Pos   ast.Pos
+}
+
+func (c Continue) Type() ObjectType { return ContinueObj }
+func (c Continue) Eval() string     { return "continue" }
 
 type FuncDecl struct {
 	ReturnType ast.Type
@@ -145,10 +551,45 @@ type FuncDecl struct {
 func (fd FuncDecl) Type() ObjectType { return FuncDeclObj }
 func (fd FuncDecl) Eval() string     { return "function" }
 
+type StructDecl struct {
+	Ident  ast.Identifier
+	Fields []ast.VarDecl
+	State  *State
+}
+
+func (sd StructDecl) Type() ObjectType { return StructDeclObj }
+func (sd StructDecl) Eval() string     { return "struct " + sd.Ident.Name }
+
+// Enum is a member of an enumerated type. Value is its position in the
+// declaration, which orders the members for comparisons.
+type Enum struct {
+	Name   string
+	Member string
+	Value  int
+}
+
+func (e Enum) Type() ObjectType { return EnumObj }
+func (e Enum) Eval() string     { return e.Member }
+
+type EnumDecl struct {
+	Ident   ast.Identifier
+	Members []Enum
+}
+
+func (ed EnumDecl) Type() ObjectType { return EnumDeclObj }
+func (ed EnumDecl) Eval() string     { return "enum " + ed.Ident.Name }
+
 type BuiltInFunc func(args ...Object) Object
 
+// BuiltIn is a built-in function. Element is the position of the argument,
+// if any, that is stored in or looked up in the container passed first, so
+// that an integer literal there takes on the container's element or key type.
+// Mutates is set if the function modifies that container, which therefore
+// cannot be a constant.
 type BuiltIn struct {
 	Function BuiltInFunc
+	Element  int
+	Mutates  bool
 }
 
 func (b BuiltIn) Type() ObjectType { return BuiltInObj }
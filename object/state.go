@@ -12,42 +12,94 @@
 package object
 
 type State struct {
-	store map[string]Object
+	store  map[string]Object
+	consts map[string]bool
+	outer  *State
+	frame  bool
 }
 
 func NewState() *State {
 	store := make(map[string]Object)
-	return &State{store: store}
+	consts := make(map[string]bool)
+	return &State{store: store, consts: consts}
 }
 
-func NewCopiedState(s *State) *State {
-	store := make(map[string]Object)
-	for key, val := range s.store {
-		store[key] = val
-	}
-	return &State{store: store}
+func NewEnclosedState(outer *State) *State {
+	state := NewState()
+	state.outer = outer
+	return state
 }
 
-func UpdateState(s *State, t *State) {
-	for key, _ := range s.store {
-		s.store[key] = t.store[key]
-	}
-}
-
-func CopyFunctions(s *State, t *State) {
-	for key, val := range t.store {
-		if val.Type() == FuncDeclObj {
-			s.store[key] = t.store[key]
-		}
-	}
+// NewFrameState encloses outer in a state for a function call, so that
+// declarations inside the function may shadow the names it can see.
+func NewFrameState(outer *State) *State {
+	state := NewEnclosedState(outer)
+	state.frame = true
+	return state
 }
 
 func (s *State) Get(id string) (Object, bool) {
 	obj, ok := s.store[id]
+	if !ok && s.outer != nil {
+		return s.outer.Get(id)
+	}
 	return obj, ok
 }
 
+// Declared reports whether id is declared in this state or an enclosing one
+// without crossing the boundary of a function call.
+func (s *State) Declared(id string) bool {
+	if _, ok := s.store[id]; ok {
+		return true
+	}
+	if s.frame || s.outer == nil {
+		return false
+	}
+	return s.outer.Declared(id)
+}
+
 func (s *State) Set(id string, val Object) Object {
 	s.store[id] = val
 	return val
 }
+
+// SetConst declares id in this state as a constant that cannot be assigned.
+func (s *State) SetConst(id string, val Object) Object {
+	s.consts[id] = true
+	return s.Set(id, val)
+}
+
+// Const reports whether the closest enclosing declaration of id is a
+// constant.
+func (s *State) Const(id string) bool {
+	for state := s; state != nil; state = state.outer {
+		if _, ok := state.store[id]; ok {
+			return state.consts[id]
+		}
+	}
+	return false
+}
+
+// Update assigns val to the closest enclosing declaration of id.
+func (s *State) Update(id string, val Object) Object {
+	for state := s; state != nil; state = state.outer {
+		if _, ok := state.store[id]; ok {
+			state.store[id] = val
+			return val
+		}
+	}
+	s.store[id] = val
+	return val
+}
+
+// Names lists every name visible from this state, for suggesting one in
+// place of a name that is not.
+func (s *State) Names() []string {
+	var names []string
+	for state := s; state != nil; state = state.outer {
+		for id := range state.store {
+			names = append(names, id)
+		}
+	}
+	return names
+}
@@ -9,97 +9,128 @@ import __yyfmt__ "fmt"
 
 import (
 	"ariel/ast"
-	"ariel/color"
-	"ariel/misc"
+	"ariel/object"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"strings"
 	"text/scanner"
 )
 
 type Token struct {
+	Pos     ast.Pos
 	Literal string
 	Int     int64
 	Float   float64
 	Bool    bool
 }
 
-//line parser.y:24
+//line parser.y:23
 type yySymType struct {
-	yys       int
-	token     Token
-	Program   ast.Program
-	DeclList  []ast.Statement
-	Decl      ast.Statement
-	Type      ast.Type
-	FuncDecl  ast.FuncDecl
-	VarDecl   ast.VarDecl
-	ParamList []ast.Param
-	Param     ast.Param
-	Block     ast.Block
-	StmtList  []ast.Statement
-	Stmt      ast.Statement
-	While     ast.While
-	For       ast.For
-	IfElse    ast.IfElse
-	Return    ast.Return
-	ExprStmt  ast.ExprStmt
-	Expr      ast.Expression
-	Call      ast.Call
-	Array     ast.Array
-	ExprList  []ast.Expression
-	Id        ast.Identifier
+	yys        int
+	token      Token
+	Program    ast.Program
+	DeclList   []ast.Statement
+	Decl       ast.Statement
+	Type       ast.Type
+	Types      []ast.Type
+	FuncDecl   ast.FuncDecl
+	VarDecl    ast.VarDecl
+	TupleDecl  ast.TupleDecl
+	TargetList []ast.VarDecl
+	StructDecl ast.StructDecl
+	EnumDecl   ast.EnumDecl
+	IdList     []ast.Identifier
+	FieldList  []ast.VarDecl
+	ParamList  []ast.Param
+	Param      ast.Param
+	Lambda     ast.Lambda
+	Block      ast.Block
+	StmtList   []ast.Statement
+	Stmt       ast.Statement
+	While      ast.While
+	DoWhile    ast.DoWhile
+	For        ast.For
+	ForEach    ast.ForEach
+	IfElse     ast.IfElse
+	Switch     ast.Switch
+	CaseList   []ast.Case
+	Case       ast.Case
+	Return     ast.Return
+	Break      ast.Break
+	Continue   ast.Continue
+	ExprStmt   ast.ExprStmt
+	Expr       ast.Expression
+	Call       ast.Call
+	Array      ast.Array
+	ExprList   []ast.Expression
+	Depth      int
+	Id         ast.Identifier
 }
 
 const CHAR = 57346
 const INT = 57347
-const FLOAT = 57348
-const STRING = 57349
-const BOOL = 57350
-const VOID = 57351
-const WHILE = 57352
-const FOR = 57353
-const IF = 57354
-const ELSE = 57355
-const RETURN = 57356
-const LT = 57357
-const LE = 57358
-const EQ = 57359
-const GE = 57360
-const GT = 57361
-const AND = 57362
-const OR = 57363
-const ADD = 57364
-const SUB = 57365
-const MUL = 57366
-const DIV = 57367
-const MOD = 57368
-const RSHIFT = 57369
-const LSHIFT = 57370
-const ADDS = 57371
-const SUBS = 57372
-const MULS = 57373
-const DIVS = 57374
-const MODS = 57375
-const LSHIFTS = 57376
-const RSHIFTS = 57377
-const ID = 57378
-const CHARCON = 57379
-const INTCON = 57380
-const STRINGCON = 57381
-const FLOATCON = 57382
-const TRUE = 57383
-const FALSE = 57384
-const ANDS = 57385
-const XORS = 57386
-const ORS = 57387
-const NE = 57388
-const NEG = 57389
-const POS = 57390
-const NOT = 57391
-const TILDE = 57392
+const SIZED = 57348
+const BIGINT = 57349
+const FLOAT = 57350
+const STRING = 57351
+const BOOL = 57352
+const VOID = 57353
+const STRUCT = 57354
+const ENUM = 57355
+const MAP = 57356
+const CONST = 57357
+const WHILE = 57358
+const DO = 57359
+const FOR = 57360
+const IF = 57361
+const ELSE = 57362
+const RETURN = 57363
+const BREAK = 57364
+const CONTINUE = 57365
+const SWITCH = 57366
+const CASE = 57367
+const DEFAULT = 57368
+const FALLTHROUGH = 57369
+const LT = 57370
+const LE = 57371
+const EQ = 57372
+const GE = 57373
+const GT = 57374
+const AND = 57375
+const OR = 57376
+const ADD = 57377
+const SUB = 57378
+const MUL = 57379
+const DIV = 57380
+const MOD = 57381
+const RSHIFT = 57382
+const LSHIFT = 57383
+const ADDS = 57384
+const SUBS = 57385
+const MULS = 57386
+const DIVS = 57387
+const MODS = 57388
+const LSHIFTS = 57389
+const RSHIFTS = 57390
+const INC = 57391
+const DEC = 57392
+const ID = 57393
+const CHARCON = 57394
+const INTCON = 57395
+const BIGINTCON = 57396
+const STRINGCON = 57397
+const FLOATCON = 57398
+const TRUE = 57399
+const FALSE = 57400
+const ANDS = 57401
+const XORS = 57402
+const ORS = 57403
+const NE = 57404
+const NEG = 57405
+const POS = 57406
+const NOT = 57407
+const TILDE = 57408
 
 var yyToknames = [...]string{
 	"$end",
@@ -107,15 +138,28 @@ var yyToknames = [...]string{
 	"$unk",
 	"CHAR",
 	"INT",
+	"SIZED",
+	"BIGINT",
 	"FLOAT",
 	"STRING",
 	"BOOL",
 	"VOID",
+	"STRUCT",
+	"ENUM",
+	"MAP",
+	"CONST",
 	"WHILE",
+	"DO",
 	"FOR",
 	"IF",
 	"ELSE",
 	"RETURN",
+	"BREAK",
+	"CONTINUE",
+	"SWITCH",
+	"CASE",
+	"DEFAULT",
+	"FALLTHROUGH",
 	"'+'",
 	"'-'",
 	"'*'",
@@ -126,6 +170,7 @@ var yyToknames = [...]string{
 	"'|'",
 	"'='",
 	"'!'",
+	"'~'",
 	"LT",
 	"LE",
 	"EQ",
@@ -147,9 +192,12 @@ var yyToknames = [...]string{
 	"MODS",
 	"LSHIFTS",
 	"RSHIFTS",
+	"INC",
+	"DEC",
 	"ID",
 	"CHARCON",
 	"INTCON",
+	"BIGINTCON",
 	"STRINGCON",
 	"FLOATCON",
 	"TRUE",
@@ -161,9 +209,12 @@ var yyToknames = [...]string{
 	"'['",
 	"']'",
 	"';'",
+	"':'",
+	"'.'",
 	"ANDS",
 	"XORS",
 	"ORS",
+	"'?'",
 	"NE",
 	"'<'",
 	"'>'",
@@ -172,7 +223,6 @@ var yyToknames = [...]string{
 	"NOT",
 	"TILDE",
 	"','",
-	"'~'",
 }
 
 var yyStatenames = [...]string{}
@@ -181,16 +231,18 @@ const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-//line parser.y:421
+//line parser.y:888
 
 type Lexer struct {
 	scanner.Scanner
 	result ast.Program
+	errors []error
 	debug  bool
 }
 
 func (l *Lexer) Lex(lval *yySymType) int {
 	var ttype int
+	errs := l.ErrorCount
 	token := l.Scan()
 	lit := l.TokenText()
 	tok := int(token)
@@ -217,6 +269,13 @@ func (l *Lexer) Lex(lval *yySymType) int {
 		}
 	}
 
+	if lit == "+" || lit == "-" {
+		if l.Peek() == rune(lit[0]) {
+			l.Next()
+			lit += lit
+		}
+	}
+
 	if lit == "<" {
 		if l.Peek() == '<' {
 			l.Next()
@@ -267,49 +326,76 @@ func (l *Lexer) Lex(lval *yySymType) int {
 	}
 
 	var reserved = map[string]int{
-		"char":   CHAR,
-		"int":    INT,
-		"float":  FLOAT,
-		"string": STRING,
-		"bool":   BOOL,
-		"void":   VOID,
-		"while":  WHILE,
-		"for":    FOR,
-		"if":     IF,
-		"else":   ELSE,
-		"return": RETURN,
-		"true":   TRUE,
-		"false":  FALSE,
-		"<=":     LE,
-		"==":     EQ,
-		"!=":     NE,
-		">=":     GE,
-		"+=":     ADDS,
-		"-=":     SUBS,
-		"*=":     MULS,
-		"/=":     DIVS,
-		"%=":     MODS,
-		"&=":     ANDS,
-		"^=":     XORS,
-		"|=":     ORS,
-		"&&":     AND,
-		"||":     OR,
-		"<<":     LSHIFT,
-		">>":     RSHIFT,
-		"<<=":    LSHIFTS,
-		">>=":    RSHIFTS,
+		"char":        CHAR,
+		"int":         INT,
+		"i8":          SIZED,
+		"i16":         SIZED,
+		"i32":         SIZED,
+		"i64":         SIZED,
+		"u8":          SIZED,
+		"u16":         SIZED,
+		"u32":         SIZED,
+		"u64":         SIZED,
+		"bigint":      BIGINT,
+		"float":       FLOAT,
+		"string":      STRING,
+		"bool":        BOOL,
+		"void":        VOID,
+		"const":       CONST,
+		"struct":      STRUCT,
+		"enum":        ENUM,
+		"map":         MAP,
+		"while":       WHILE,
+		"do":          DO,
+		"for":         FOR,
+		"if":          IF,
+		"else":        ELSE,
+		"return":      RETURN,
+		"break":       BREAK,
+		"continue":    CONTINUE,
+		"switch":      SWITCH,
+		"case":        CASE,
+		"default":     DEFAULT,
+		"fallthrough": FALLTHROUGH,
+		"true":        TRUE,
+		"false":       FALSE,
+		"<=":          LE,
+		"==":          EQ,
+		"!=":          NE,
+		">=":          GE,
+		"+=":          ADDS,
+		"-=":          SUBS,
+		"*=":          MULS,
+		"/=":          DIVS,
+		"%=":          MODS,
+		"&=":          ANDS,
+		"^=":          XORS,
+		"|=":          ORS,
+		"++":          INC,
+		"--":          DEC,
+		"&&":          AND,
+		"||":          OR,
+		"<<":          LSHIFT,
+		">>":          RSHIFT,
+		"<<=":         LSHIFTS,
+		">>=":         RSHIFTS,
 	}
 
 	if t, ok := reserved[lit]; ok {
 		ttype = t
 	}
 
-	lval.token = Token{Literal: lit}
+	lval.token = Token{
+		Pos:     l.pos(),
+		Literal: lit,
+	}
 
 	switch ttype {
 	case INTCON:
 		if i, err := strconv.ParseInt(lit, 10, 64); err == nil {
 			lval.token.Int = i
+		} else {
+			ttype = BIGINTCON
 		}
 	case FLOATCON:
 		if f, err := strconv.ParseFloat(lit, 64); err == nil {
@@ -319,8 +405,13 @@ func (l *Lexer) Lex(lval *yySymType) int {
 		lval.token.Literal = strings.TrimPrefix(lval.token.Literal, "\"")
 		lval.token.Literal = strings.TrimSuffix(lval.token.Literal, "\"")
 	case CHARCON:
-		lval.token.Literal = strings.TrimPrefix(lval.token.Literal, "'")
-		lval.token.Literal = strings.TrimSuffix(lval.token.Literal, "'")
+		r, _, tail, err := strconv.UnquoteChar(lit[1:], '\'')
+		// A malformed literal is a syntax error, unless the scanner has
+		// already reported it.
+		if (err != nil || tail != "'") && l.ErrorCount == errs {
+			l.Error("invalid char literal " + lit)
+		}
+		lval.token.Int = int64(r)
 	case TRUE:
 		lval.token.Bool = true
 	case FALSE:
@@ -334,283 +425,743 @@ func (l *Lexer) Lex(lval *yySymType) int {
 	return ttype
 }
 
+func incDec(l yyLexer, pos ast.Pos, target ast.Expression, op string, prefix bool) ast.Expression {
+	switch target := target.(type) {
+	case ast.Identifier:
+		return ast.IncDecExpr{Pos: pos, Ident: target, Op: op, Prefix: prefix}
+	case ast.IndexExpr:
+		return ast.IncDecIndexExpr{
+			Pos:    pos,
+			Left:   target.Left,
+			Index:  target.Index,
+			Op:     op,
+			Prefix: prefix,
+		}
+	case ast.FieldExpr:
+		return ast.IncDecFieldExpr{
+			Pos:    pos,
+			Left:   target.Left,
+			Field:  target.Field,
+			Op:     op,
+			Prefix: prefix,
+		}
+	default:
+		l.Error("invalid operand for " + op)
+		return target
+	}
+}
+
+// fold evaluates operators applied to literals while parsing, so that an
+// expression such as 60 * 60 * 24 reaches the evaluator as a single literal.
+// Only literal operands are folded: the parser does not track scopes, so a
+// name declared const, as in N * 2, is left to be looked up at runtime.
+// Anything that would be an error at runtime, such as division by zero, is
+// also left for the evaluator to report.
+func fold(e ast.Expression) ast.Expression {
+	pos := e.Position()
+	switch e := e.(type) {
+	case ast.PrefixExpr:
+		switch right := e.Right.(type) {
+		case ast.IntCon:
+			switch e.Op {
+			case "-":
+				return ast.IntCon{Pos: pos, Value: -right.Value}
+			case "~":
+				return ast.IntCon{Pos: pos, Value: ^right.Value}
+			}
+		case ast.FloatCon:
+			if e.Op == "-" {
+				return ast.FloatCon{Pos: pos, Value: -right.Value}
+			}
+		case ast.Bool:
+			if e.Op == "!" {
+				return ast.Bool{Pos: pos, Value: !right.Value}
+			}
+		}
+	case ast.InfixExpr:
+		switch left := e.Left.(type) {
+		case ast.IntCon:
+			if right, ok := e.Right.(ast.IntCon); ok {
+				if folded := foldInt(pos, e.Op, left.Value, right.Value); folded != nil {
+					return folded
+				}
+			}
+		case ast.FloatCon:
+			if right, ok := e.Right.(ast.FloatCon); ok {
+				if folded := foldFloat(pos, e.Op, left.Value, right.Value); folded != nil {
+					return folded
+				}
+			}
+		case ast.StringCon:
+			if right, ok := e.Right.(ast.StringCon); ok {
+				switch e.Op {
+				case "+":
+					return ast.StringCon{Pos: pos, Value: left.Value + right.Value}
+				case "==":
+					return ast.Bool{Pos: pos, Value: left.Value == right.Value}
+				case "!=":
+					return ast.Bool{Pos: pos, Value: left.Value != right.Value}
+				}
+			}
+		case ast.Bool:
+			if right, ok := e.Right.(ast.Bool); ok {
+				switch e.Op {
+				case "&&":
+					return ast.Bool{Pos: pos, Value: left.Value && right.Value}
+				case "||":
+					return ast.Bool{Pos: pos, Value: left.Value || right.Value}
+				case "==":
+					return ast.Bool{Pos: pos, Value: left.Value == right.Value}
+				case "!=":
+					return ast.Bool{Pos: pos, Value: left.Value != right.Value}
+				}
+			}
+		}
+	}
+	return e
+}
+
+func foldInt(pos ast.Pos, op string, l, r int64) ast.Expression {
+	switch op {
+	case "<":
+		return ast.Bool{Pos: pos, Value: l < r}
+	case "<=":
+		return ast.Bool{Pos: pos, Value: l <= r}
+	case "==":
+		return ast.Bool{Pos: pos, Value: l == r}
+	case "!=":
+		return ast.Bool{Pos: pos, Value: l != r}
+	case ">=":
+		return ast.Bool{Pos: pos, Value: l >= r}
+	case ">":
+		return ast.Bool{Pos: pos, Value: l > r}
+	case "+":
+		return ast.IntCon{Pos: pos, Value: l + r}
+	case "-":
+		return ast.IntCon{Pos: pos, Value: l - r}
+	case "*":
+		return ast.IntCon{Pos: pos, Value: l * r}
+	case "/":
+		if r != 0 {
+			return ast.IntCon{Pos: pos, Value: l / r}
+		}
+	case "%":
+		if r != 0 {
+			return ast.IntCon{Pos: pos, Value: l % r}
+		}
+	case "&":
+		return ast.IntCon{Pos: pos, Value: l & r}
+	case "^":
+		return ast.IntCon{Pos: pos, Value: l ^ r}
+	case "|":
+		return ast.IntCon{Pos: pos, Value: l | r}
+	case "<<":
+		if r >= 0 {
+			return ast.IntCon{Pos: pos, Value: l << r}
+		}
+	case ">>":
+		if r >= 0 {
+			return ast.IntCon{Pos: pos, Value: l >> r}
+		}
+	}
+	return nil
+}
+
+func foldFloat(pos ast.Pos, op string, l, r float64) ast.Expression {
+	switch op {
+	case "<":
+		return ast.Bool{Pos: pos, Value: l < r}
+	case "<=":
+		return ast.Bool{Pos: pos, Value: l <= r}
+	case "==":
+		return ast.Bool{Pos: pos, Value: l == r}
+	case "!=":
+		return ast.Bool{Pos: pos, Value: l != r}
+	case ">=":
+		return ast.Bool{Pos: pos, Value: l >= r}
+	case ">":
+		return ast.Bool{Pos: pos, Value: l > r}
+	case "+":
+		return ast.FloatCon{Pos: pos, Value: l + r}
+	case "-":
+		return ast.FloatCon{Pos: pos, Value: l - r}
+	case "*":
+		return ast.FloatCon{Pos: pos, Value: l * r}
+	case "/":
+		if r != 0 {
+			return ast.FloatCon{Pos: pos, Value: l / r}
+		}
+	}
+	return nil
+}
+
+// Error records a syntax error at the current token; parsing carries on
+// from the next statement, so that one run reports as many as it can.
 func (l *Lexer) Error(e string) {
-	err := fmt.Sprintf("%s: line %d, column %d",
-		e, l.Position.Line, l.Position.Column)
-	fmt.Fprintln(os.Stderr, color.Red+misc.Flounder(err)+color.Reset)
+	l.errorAt(l.pos(), e)
 }
 
-func ParseProgram(input io.Reader, debug bool) ast.Program {
-	l := new(Lexer)
-	l.debug = debug
-	l.Init(input)
-	l.Mode = scanner.ScanIdents | scanner.ScanFloats | scanner.ScanChars
-	l.Mode |= scanner.ScanStrings | scanner.SkipComments
+// errorAt records an error found once the tokens at pos have been parsed.
+func (l *Lexer) errorAt(pos ast.Pos, e string) {
+	l.errors = append(l.errors, object.Error{Message: e, Pos: pos})
+}
+
+// pos is where the current token starts.
+func (l *Lexer) pos() ast.Pos {
+	return ast.Pos{
+		File:   l.Position.Filename,
+		Line:   l.Position.Line,
+		Column: l.Position.Column,
+	}
+}
+
+// ParseProgram parses a program read from input, returning it along with
+// any syntax errors found. The program should not be run if there are any.
+func ParseProgram(input io.Reader, debug bool) (ast.Program, []error) {
+	l := newLexer(input, debug)
+	// Positions name the file being parsed, when there is one.
+	if file, ok := input.(interface{ Name() string }); ok {
+		l.Filename = file.Name()
+	}
+	yyParse(l)
+	return l.result, l.errors
+}
+
+func ParseProgramString(input string, debug bool) (ast.Program, []error) {
+	l := newLexer(strings.NewReader(input), debug)
 	yyParse(l)
-	return l.result
+	return l.result, l.errors
 }
 
-func ParseProgramString(input string, debug bool) ast.Program {
+func newLexer(input io.Reader, debug bool) *Lexer {
 	l := new(Lexer)
 	l.debug = debug
-	l.Init(strings.NewReader(input))
+	l.Init(input)
 	l.Mode = scanner.ScanIdents | scanner.ScanFloats | scanner.ScanChars
 	l.Mode |= scanner.ScanStrings | scanner.SkipComments
-	yyParse(l)
-	return l.result
+	l.Scanner.Error = func(_ *scanner.Scanner, msg string) { l.Error(msg) }
+	return l
 }
 
 //line yacctab:1
-var yyExca = [...]int{
+var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
+	-1, 2,
+	1, 1,
+	-2, 0,
+	-1, 384,
+	25, 93,
+	26, 93,
+	73, 93,
+	-2, 0,
+	-1, 388,
+	25, 93,
+	26, 93,
+	73, 93,
+	-2, 0,
+	-1, 390,
+	25, 94,
+	26, 94,
+	73, 94,
+	-2, 0,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 1057
-
-var yyAct = [...]int{
-	19, 7, 4, 131, 45, 13, 136, 13, 197, 149,
-	146, 48, 192, 174, 47, 191, 173, 44, 14, 50,
-	176, 46, 147, 39, 157, 150, 147, 154, 83, 84,
-	85, 86, 87, 179, 20, 21, 22, 23, 24, 25,
-	52, 53, 54, 55, 56, 175, 93, 95, 96, 98,
-	32, 12, 97, 100, 101, 102, 103, 104, 105, 106,
-	107, 108, 109, 110, 111, 112, 113, 114, 115, 116,
-	117, 118, 119, 120, 121, 122, 123, 124, 125, 126,
-	127, 128, 129, 132, 134, 26, 28, 27, 11, 10,
-	9, 138, 139, 137, 3, 29, 8, 40, 90, 41,
-	43, 90, 135, 52, 53, 54, 55, 56, 54, 55,
-	56, 6, 5, 2, 63, 1, 66, 39, 33, 34,
-	36, 35, 37, 38, 31, 61, 60, 0, 88, 140,
-	0, 94, 91, 0, 89, 91, 148, 89, 0, 0,
-	0, 0, 30, 156, 155, 0, 0, 158, 170, 0,
-	0, 171, 62, 67, 0, 137, 0, 172, 178, 0,
-	180, 181, 182, 183, 184, 185, 186, 187, 188, 189,
-	190, 20, 21, 22, 23, 24, 25, 132, 194, 0,
-	193, 0, 196, 143, 0, 0, 0, 20, 21, 22,
-	23, 24, 25, 15, 16, 17, 199, 18, 28, 27,
-	200, 0, 202, 0, 0, 204, 0, 29, 0, 0,
+const yyLast = 2740
+
+var yyAct = [...]int16{
+	36, 11, 389, 99, 358, 8, 244, 292, 169, 176,
+	46, 388, 294, 171, 342, 168, 65, 142, 174, 85,
+	220, 84, 141, 84, 192, 142, 304, 339, 384, 84,
+	253, 343, 242, 70, 100, 298, 287, 280, 398, 219,
+	231, 91, 4, 305, 4, 219, 340, 314, 134, 135,
+	136, 137, 138, 139, 140, 230, 178, 132, 173, 230,
+	240, 309, 239, 396, 315, 316, 317, 318, 319, 323,
+	324, 94, 177, 151, 152, 153, 154, 155, 156, 157,
+	158, 159, 160, 161, 100, 227, 167, 163, 225, 299,
+	320, 321, 322, 174, 185, 376, 187, 189, 190, 349,
+	348, 174, 310, 345, 228, 289, 194, 192, 193, 195,
+	196, 197, 198, 199, 200, 201, 202, 203, 204, 205,
+	206, 207, 208, 209, 210, 211, 212, 213, 191, 70,
+	214, 178, 183, 173, 142, 172, 248, 297, 84, 178,
+	285, 173, 192, 132, 38, 39, 40, 41, 42, 43,
+	44, 45, 67, 68, 63, 177, 101, 23, 142, 23,
+	37, 241, 84, 27, 37, 164, 37, 226, 51, 50,
+	255, 312, 142, 217, 105, 236, 84, 52, 53, 247,
+	103, 86, 87, 88, 23, 250, 23, 237, 234, 238,
+	85, 104, 106, 260, 84, 276, 180, 127, 128, 242,
+	48, 49, 37, 56, 57, 58, 60, 59, 61, 62,
+	47, 129, 359, 360, 142, 130, 264, 215, 84, 37,
+	179, 279, 97, 143, 144, 145, 96, 85, 95, 217,
+	93, 84, 15, 283, 37, 286, 14, 281, 355, 293,
+	13, 295, 284, 186, 282, 3, 290, 23, 64, 184,
+	110, 111, 112, 175, 296, 303, 307, 308, 390, 12,
+	381, 54, 313, 21, 325, 20, 328, 329, 330, 331,
+	332, 333, 334, 335, 336, 337, 338, 19, 359, 360,
+	127, 128, 28, 29, 30, 18, 90, 216, 357, 341,
+	17, 16, 55, 166, 129, 249, 306, 251, 130, 346,
+	311, 7, 102, 24, 150, 24, 347, 6, 149, 243,
+	353, 354, 148, 66, 9, 361, 362, 363, 364, 365,
+	366, 367, 368, 369, 370, 371, 356, 232, 5, 277,
+	92, 147, 92, 2, 245, 1, 0, 252, 0, 0,
+	0, 0, 0, 0, 293, 257, 0, 0, 0, 131,
+	133, 374, 0, 377, 0, 0, 0, 0, 0, 0,
+	100, 0, 382, 383, 0, 0, 0, 0, 0, 0,
+	0, 0, 146, 0, 0, 0, 0, 0, 0, 386,
+	0, 387, 0, 0, 0, 0, 0, 392, 170, 0,
+	0, 394, 232, 92, 397, 0, 0, 380, 188, 0,
+	0, 400, 0, 300, 0, 108, 109, 110, 111, 112,
+	0, 23, 0, 0, 0, 23, 0, 108, 109, 110,
+	111, 112, 113, 114, 115, 0, 0, 0, 0, 119,
+	120, 122, 183, 124, 125, 218, 0, 127, 128, 0,
+	117, 116, 0, 0, 222, 224, 0, 0, 0, 127,
+	128, 129, 0, 0, 0, 130, 245, 0, 0, 0,
+	0, 0, 350, 129, 262, 0, 263, 130, 0, 0,
+	72, 126, 121, 118, 123, 235, 0, 0, 0, 0,
+	246, 0, 66, 0, 0, 0, 0, 73, 74, 75,
+	76, 77, 81, 82, 108, 109, 110, 111, 112, 113,
+	114, 115, 0, 0, 83, 0, 119, 120, 122, 0,
+	124, 125, 23, 78, 79, 80, 0, 117, 116, 0,
+	0, 0, 278, 0, 0, 0, 127, 128, 0, 0,
+	0, 224, 0, 235, 0, 0, 0, 399, 0, 0,
+	129, 23, 0, 0, 130, 23, 0, 23, 126, 121,
+	118, 123, 66, 0, 0, 0, 0, 92, 0, 0,
+	182, 92, 38, 39, 40, 41, 42, 43, 44, 45,
+	67, 68, 63, 10, 28, 29, 30, 31, 0, 33,
+	34, 35, 32, 0, 0, 0, 51, 50, 0, 265,
+	0, 0, 0, 0, 0, 52, 53, 0, 0, 0,
+	0, 0, 246, 0, 0, 0, 266, 267, 268, 269,
+	270, 274, 275, 0, 0, 0, 0, 0, 48, 49,
+	37, 56, 57, 58, 60, 59, 61, 62, 47, 0,
+	27, 181, 271, 272, 273, 0, 0, 0, 0, 0,
+	0, 0, 0, 373, 38, 39, 40, 41, 42, 43,
+	44, 45, 67, 68, 63, 0, 0, 0, 92, 22,
+	0, 38, 39, 40, 41, 42, 43, 44, 45, 67,
+	68, 63, 10, 28, 29, 30, 31, 0, 33, 34,
+	35, 32, 0, 0, 0, 51, 50, 92, 0, 0,
+	0, 92, 0, 92, 52, 53, 38, 39, 40, 41,
+	42, 43, 44, 45, 67, 68, 63, 0, 0, 0,
+	69, 0, 0, 302, 0, 0, 0, 48, 49, 37,
+	56, 57, 58, 60, 59, 61, 62, 47, 0, 27,
+	89, 22, 0, 38, 39, 40, 41, 42, 43, 44,
+	45, 67, 68, 63, 10, 28, 29, 30, 31, 0,
+	33, 34, 35, 32, 0, 0, 395, 51, 50, 0,
+	0, 0, 69, 233, 0, 0, 52, 53, 0, 0,
+	0, 38, 39, 40, 41, 42, 43, 44, 45, 67,
+	68, 63, 0, 0, 0, 0, 0, 0, 0, 48,
+	49, 37, 56, 57, 58, 60, 59, 61, 62, 47,
+	22, 27, 38, 39, 40, 41, 42, 43, 44, 45,
+	67, 68, 63, 10, 28, 29, 30, 31, 0, 33,
+	34, 35, 32, 0, 0, 391, 51, 50, 0, 0,
+	0, 0, 0, 0, 0, 52, 53, 69, 223, 38,
+	39, 40, 41, 42, 43, 44, 45, 67, 68, 63,
+	0, 0, 0, 0, 0, 0, 0, 0, 48, 49,
+	37, 56, 57, 58, 60, 59, 61, 62, 47, 0,
+	27, 108, 109, 110, 111, 112, 113, 114, 115, 0,
+	0, 0, 0, 119, 120, 122, 0, 124, 125, 0,
+	0, 0, 0, 0, 117, 116, 0, 37, 0, 0,
+	0, 0, 0, 127, 128, 69, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 129, 0, 393,
+	0, 130, 0, 0, 0, 126, 121, 118, 123, 108,
+	109, 110, 111, 112, 113, 114, 115, 0, 0, 0,
+	0, 119, 120, 122, 0, 124, 125, 0, 0, 0,
+	0, 0, 117, 116, 0, 0, 0, 0, 0, 0,
+	0, 127, 128, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 385, 0, 0, 129, 0, 0, 0, 130,
+	0, 0, 0, 126, 121, 118, 123, 22, 0, 38,
+	39, 40, 41, 42, 43, 44, 45, 67, 68, 63,
+	10, 28, 29, 30, 31, 0, 33, 34, 35, 32,
+	0, 0, 0, 51, 50, 0, 0, 0, 0, 0,
+	0, 0, 52, 53, 0, 0, 38, 39, 40, 41,
+	42, 43, 44, 45, 67, 68, 63, 0, 0, 0,
+	0, 0, 0, 0, 0, 48, 49, 37, 56, 57,
+	58, 60, 59, 61, 62, 47, 0, 27, 108, 109,
+	110, 111, 112, 113, 114, 115, 0, 0, 0, 0,
+	119, 120, 122, 0, 124, 125, 0, 0, 0, 0,
+	0, 117, 116, 0, 0, 0, 0, 0, 0, 0,
+	127, 128, 69, 108, 109, 110, 111, 112, 113, 114,
+	115, 379, 0, 0, 129, 119, 120, 122, 130, 124,
+	125, 0, 126, 121, 118, 123, 117, 116, 0, 0,
+	0, 0, 0, 0, 0, 127, 128, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 129,
+	0, 378, 0, 130, 0, 0, 0, 126, 121, 118,
+	123, 108, 109, 110, 111, 112, 113, 114, 115, 0,
+	0, 0, 0, 119, 120, 122, 0, 124, 125, 0,
+	0, 0, 0, 0, 117, 116, 0, 0, 0, 0,
+	0, 0, 0, 127, 128, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 129, 0, 375,
+	0, 130, 0, 0, 0, 126, 121, 118, 123, 108,
+	109, 110, 111, 112, 113, 114, 115, 0, 0, 0,
+	0, 119, 120, 122, 0, 124, 125, 0, 0, 0,
+	0, 0, 117, 116, 0, 0, 0, 0, 0, 0,
+	0, 127, 128, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 129, 372, 0, 0, 130,
+	0, 0, 0, 126, 121, 118, 123, 108, 109, 110,
+	111, 112, 113, 114, 115, 0, 0, 0, 0, 119,
+	120, 122, 0, 124, 125, 0, 0, 0, 0, 0,
+	117, 116, 0, 0, 0, 0, 0, 0, 0, 127,
+	128, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 129, 0, 352, 0, 130, 0, 0,
+	0, 126, 121, 118, 123, 108, 109, 110, 111, 112,
+	113, 114, 115, 0, 0, 0, 0, 119, 120, 122,
+	0, 124, 125, 0, 0, 0, 0, 0, 117, 116,
+	0, 0, 0, 0, 0, 0, 0, 127, 128, 0,
+	108, 109, 110, 111, 112, 113, 114, 115, 351, 0,
+	0, 129, 119, 120, 122, 130, 124, 125, 0, 126,
+	121, 118, 123, 117, 116, 0, 0, 0, 0, 0,
+	0, 0, 127, 128, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 129, 344, 0, 0,
+	130, 0, 0, 0, 126, 121, 118, 123, 108, 109,
+	110, 111, 112, 113, 114, 115, 0, 0, 0, 0,
+	119, 120, 122, 0, 124, 125, 0, 0, 0, 0,
+	0, 117, 116, 0, 0, 0, 0, 0, 0, 0,
+	127, 128, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 129, 327, 0, 0, 130, 0,
+	0, 0, 126, 121, 118, 123, 108, 109, 110, 111,
+	112, 113, 114, 115, 0, 0, 0, 0, 119, 120,
+	122, 0, 124, 125, 0, 0, 0, 0, 0, 117,
+	116, 0, 0, 0, 0, 0, 0, 0, 127, 128,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 129, 301, 0, 0, 130, 0, 0, 0,
+	126, 121, 118, 123, 108, 109, 110, 111, 112, 113,
+	114, 115, 0, 0, 0, 0, 119, 120, 122, 0,
+	124, 125, 0, 0, 0, 0, 0, 117, 116, 0,
+	0, 0, 0, 0, 0, 0, 127, 128, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	129, 0, 288, 0, 130, 0, 0, 0, 126, 121,
+	118, 123, 108, 109, 110, 111, 112, 113, 114, 115,
+	0, 0, 0, 0, 119, 120, 122, 0, 124, 125,
+	0, 0, 0, 0, 0, 117, 116, 0, 0, 0,
+	0, 0, 0, 0, 127, 128, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 129, 0,
+	0, 261, 130, 0, 0, 0, 126, 121, 118, 123,
+	108, 109, 110, 111, 112, 113, 114, 115, 0, 0,
+	0, 0, 119, 120, 122, 0, 124, 125, 0, 0,
+	0, 0, 0, 117, 116, 0, 0, 0, 0, 0,
+	0, 0, 127, 128, 0, 108, 109, 110, 111, 112,
+	113, 114, 115, 259, 0, 0, 129, 119, 120, 122,
+	130, 124, 125, 0, 126, 121, 118, 123, 117, 116,
+	0, 0, 0, 0, 0, 0, 0, 127, 128, 0,
+	108, 109, 110, 111, 112, 113, 114, 115, 258, 0,
+	0, 129, 119, 120, 122, 130, 124, 125, 0, 126,
+	121, 118, 123, 117, 116, 0, 0, 0, 0, 0,
+	0, 0, 127, 128, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 129, 0, 256, 0,
+	130, 0, 0, 0, 126, 121, 118, 123, 108, 109,
+	110, 111, 112, 113, 114, 115, 0, 0, 0, 0,
+	119, 120, 122, 0, 124, 125, 0, 0, 0, 0,
+	0, 117, 116, 0, 0, 0, 0, 0, 0, 0,
+	127, 128, 0, 108, 109, 110, 111, 112, 113, 114,
+	115, 254, 0, 0, 129, 119, 120, 122, 130, 124,
+	125, 0, 126, 121, 118, 123, 117, 116, 0, 0,
+	0, 0, 0, 0, 0, 127, 128, 0, 108, 109,
+	110, 111, 112, 113, 114, 115, 229, 0, 0, 129,
+	119, 120, 122, 130, 124, 125, 0, 126, 121, 118,
+	123, 117, 116, 0, 0, 0, 0, 0, 0, 0,
+	127, 128, 0, 108, 109, 110, 111, 112, 113, 114,
+	115, 221, 0, 0, 129, 119, 120, 122, 130, 124,
+	125, 0, 126, 121, 118, 123, 117, 116, 0, 0,
+	0, 0, 0, 0, 0, 127, 128, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 129,
+	0, 107, 0, 130, 0, 0, 0, 126, 121, 118,
+	123, 22, 0, 38, 39, 40, 41, 42, 43, 44,
+	45, 25, 26, 63, 10, 28, 29, 30, 31, 0,
+	33, 34, 35, 32, 0, 0, 0, 51, 50, 0,
+	0, 0, 0, 0, 0, 0, 52, 53, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 151, 0, 0, 0, 0, 0, 39,
-	33, 34, 36, 35, 37, 38, 31, 0, 14, 92,
-	20, 21, 22, 23, 24, 25, 15, 16, 17, 0,
-	18, 28, 27, 0, 30, 52, 53, 54, 55, 56,
-	29, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 61, 60, 0,
-	0, 0, 39, 33, 34, 36, 35, 37, 38, 31,
-	0, 14, 42, 20, 21, 22, 23, 24, 25, 15,
-	16, 17, 0, 18, 28, 27, 0, 30, 0, 0,
-	0, 0, 0, 29, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 48,
+	49, 37, 56, 57, 58, 60, 59, 61, 62, 47,
+	0, 27, 108, 109, 110, 111, 112, 113, 114, 115,
+	0, 0, 0, 0, 119, 120, 122, 0, 124, 125,
+	0, 0, 0, 0, 0, 117, 116, 0, 0, 0,
+	0, 0, 0, 0, 127, 128, 0, 108, 109, 110,
+	111, 112, 113, 114, 115, 0, 0, 0, 129, 119,
+	120, 122, 130, 124, 0, 0, 126, 121, 118, 123,
+	117, 116, 0, 0, 0, 0, 0, 0, 0, 127,
+	128, 0, 108, 109, 110, 111, 112, 113, 114, 115,
+	0, 0, 0, 129, 119, 120, 122, 130, 0, 0,
+	0, 0, 121, 118, 123, 117, 116, 0, 0, 0,
+	0, 0, 0, 0, 127, 128, 0, 108, 109, 110,
+	111, 112, 113, 114, 0, 0, 0, 0, 129, 119,
+	120, 122, 130, 0, 0, 0, 0, 121, 118, 123,
+	117, 116, 0, 0, 0, 0, 0, 0, 0, 127,
+	128, 0, 108, 109, 110, 111, 112, 113, 0, 0,
+	0, 0, 0, 129, 119, 120, 122, 130, 0, 0,
+	0, 0, 121, 118, 123, 117, 116, 0, 0, 0,
+	0, 0, 0, 0, 127, 128, 38, 39, 40, 41,
+	42, 43, 44, 45, 67, 68, 63, 0, 129, 0,
+	0, 0, 130, 0, 0, 0, 0, 121, 118, 123,
+	51, 50, 0, 0, 0, 0, 0, 0, 0, 52,
+	53, 0, 0, 0, 0, 0, 0, 0, 0, 38,
+	39, 40, 41, 42, 43, 44, 45, 67, 68, 63,
+	0, 0, 48, 49, 37, 56, 57, 58, 60, 59,
+	61, 62, 47, 51, 50, 0, 0, 0, 98, 0,
+	0, 0, 52, 53, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 39, 33, 34, 36, 35,
-	37, 38, 31, 0, 14, 20, 21, 22, 23, 24,
-	25, 0, 0, 0, 0, 0, 28, 27, 0, 0,
-	30, 0, 0, 0, 0, 29, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 48, 49, 37, 56, 57,
+	58, 60, 59, 61, 62, 47, 0, 0, 0, 0,
+	326, 108, 109, 110, 111, 112, 0, 0, 0, 0,
+	0, 0, 0, 119, 120, 122, 0, 0, 0, 0,
+	0, 0, 0, 0, 117, 116, 0, 0, 0, 0,
+	0, 0, 0, 127, 128, 38, 39, 40, 41, 42,
+	43, 44, 45, 67, 68, 63, 0, 129, 0, 0,
+	0, 130, 0, 0, 0, 0, 121, 118, 123, 51,
+	50, 0, 0, 0, 0, 0, 0, 0, 52, 53,
+	0, 0, 0, 0, 0, 0, 0, 0, 38, 39,
+	40, 41, 42, 43, 44, 45, 67, 68, 63, 0,
+	0, 48, 49, 37, 56, 57, 58, 60, 59, 61,
+	62, 47, 51, 50, 0, 0, 248, 0, 0, 0,
+	0, 52, 53, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 39, 33, 34,
-	36, 35, 37, 38, 31, 52, 53, 54, 55, 56,
-	57, 58, 59, 0, 0, 0, 63, 64, 66, 0,
-	68, 69, 30, 0, 0, 0, 0, 61, 60, 0,
-	0, 0, 0, 0, 52, 53, 54, 55, 56, 57,
-	58, 59, 0, 0, 203, 63, 64, 66, 0, 68,
-	69, 0, 0, 65, 62, 67, 61, 60, 52, 53,
-	54, 55, 56, 57, 58, 59, 0, 0, 0, 63,
-	64, 66, 0, 68, 69, 0, 0, 0, 201, 0,
-	61, 60, 65, 62, 67, 0, 0, 52, 53, 54,
-	55, 56, 57, 58, 59, 0, 0, 198, 63, 64,
-	66, 0, 68, 69, 0, 0, 65, 62, 67, 61,
-	60, 52, 53, 54, 55, 56, 57, 58, 59, 0,
-	0, 0, 63, 64, 66, 0, 68, 69, 0, 0,
-	0, 195, 0, 61, 60, 65, 62, 67, 0, 0,
-	0, 52, 53, 54, 55, 56, 57, 58, 59, 0,
-	0, 0, 63, 64, 66, 177, 68, 69, 0, 65,
-	62, 67, 0, 61, 60, 52, 53, 54, 55, 56,
-	57, 58, 59, 0, 0, 0, 63, 64, 66, 0,
-	68, 69, 0, 0, 153, 0, 0, 61, 60, 65,
-	62, 67, 0, 0, 0, 52, 53, 54, 55, 56,
-	57, 58, 59, 0, 0, 0, 63, 64, 66, 152,
-	68, 69, 0, 65, 62, 67, 0, 61, 60, 52,
-	53, 54, 55, 56, 57, 58, 59, 0, 0, 0,
-	63, 64, 66, 0, 68, 69, 0, 0, 145, 0,
-	0, 61, 60, 65, 62, 67, 0, 0, 52, 53,
-	54, 55, 56, 57, 58, 59, 0, 0, 144, 63,
-	64, 66, 0, 68, 69, 0, 0, 65, 62, 67,
-	61, 60, 52, 53, 54, 55, 56, 57, 58, 59,
-	0, 0, 0, 63, 64, 66, 0, 68, 69, 0,
-	0, 0, 142, 0, 61, 60, 65, 62, 67, 0,
-	0, 52, 53, 54, 55, 56, 57, 58, 59, 0,
-	0, 141, 63, 64, 66, 0, 68, 69, 0, 0,
-	65, 62, 67, 61, 60, 28, 27, 0, 0, 0,
-	0, 0, 0, 0, 29, 0, 0, 0, 0, 0,
-	133, 0, 0, 0, 0, 0, 0, 0, 0, 65,
-	62, 67, 0, 0, 0, 0, 39, 33, 34, 36,
-	35, 37, 38, 31, 130, 0, 0, 52, 53, 54,
-	55, 56, 57, 58, 59, 0, 0, 0, 63, 64,
-	66, 30, 68, 69, 0, 0, 0, 0, 0, 61,
-	60, 52, 53, 54, 55, 56, 57, 58, 59, 0,
-	0, 0, 63, 64, 66, 0, 68, 69, 0, 0,
-	0, 99, 0, 61, 60, 65, 62, 67, 28, 27,
-	0, 0, 0, 0, 0, 0, 0, 29, 0, 0,
-	0, 0, 0, 28, 27, 51, 0, 0, 0, 65,
-	62, 67, 29, 0, 0, 0, 0, 0, 0, 39,
-	33, 34, 36, 35, 37, 38, 31, 0, 0, 0,
-	0, 0, 49, 0, 39, 33, 34, 36, 35, 37,
-	38, 31, 0, 0, 30, 52, 53, 54, 55, 56,
-	57, 58, 59, 0, 0, 0, 63, 64, 66, 30,
-	68, 69, 0, 0, 0, 0, 0, 61, 60, 52,
-	53, 54, 55, 56, 57, 58, 59, 0, 0, 0,
-	63, 64, 66, 0, 68, 0, 0, 0, 0, 0,
-	0, 61, 60, 65, 62, 67, 0, 0, 52, 53,
-	54, 55, 56, 57, 58, 59, 0, 0, 0, 63,
-	64, 66, 0, 0, 0, 0, 0, 65, 62, 67,
-	61, 60, 52, 53, 54, 55, 56, 57, 58, 0,
-	0, 0, 0, 63, 64, 66, 0, 0, 52, 53,
-	54, 55, 56, 57, 61, 60, 65, 62, 67, 63,
-	64, 66, 52, 53, 54, 55, 56, 0, 0, 0,
-	61, 60, 0, 63, 64, 66, 0, 0, 0, 0,
-	65, 62, 67, 70, 61, 60, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 65, 62, 67, 71,
-	72, 73, 74, 75, 79, 80, 0, 159, 0, 0,
-	65, 62, 67, 82, 0, 0, 0, 81, 0, 0,
-	76, 77, 78, 160, 161, 162, 163, 164, 168, 169,
+	0, 0, 0, 0, 48, 49, 37, 56, 57, 58,
+	60, 59, 61, 62, 47, 0, 238, 291, 108, 109,
+	110, 111, 112, 0, 0, 0, 0, 0, 0, 0,
+	119, 0, 122, 0, 0, 0, 0, 0, 0, 0,
+	0, 117, 116, 0, 0, 0, 0, 0, 0, 0,
+	127, 128, 38, 39, 40, 41, 42, 43, 44, 45,
+	67, 68, 63, 0, 129, 0, 0, 0, 130, 0,
+	0, 0, 0, 0, 118, 123, 51, 50, 0, 0,
+	0, 0, 0, 0, 0, 52, 53, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 38, 39, 40,
+	41, 42, 43, 44, 45, 67, 68, 63, 48, 49,
+	37, 56, 57, 58, 60, 59, 61, 62, 47, 0,
+	238, 51, 50, 0, 0, 0, 0, 0, 0, 0,
+	52, 53, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 38, 39, 40, 41, 42, 43, 44, 45, 67,
+	68, 63, 0, 48, 49, 37, 56, 57, 58, 60,
+	59, 61, 62, 47, 165, 51, 50, 0, 0, 0,
+	0, 0, 0, 0, 52, 53, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 38, 39, 40, 41, 42,
+	43, 44, 45, 67, 68, 63, 0, 48, 49, 37,
+	56, 57, 58, 60, 59, 61, 62, 47, 162, 51,
+	50, 0, 0, 0, 0, 0, 0, 0, 52, 53,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 165, 166, 167,
+	0, 0, 0, 0, 0, 72, 0, 0, 0, 0,
+	0, 48, 49, 37, 56, 57, 58, 60, 59, 61,
+	62, 47, 73, 74, 75, 76, 77, 81, 82, 108,
+	109, 110, 111, 112, 0, 0, 0, 0, 0, 83,
+	0, 0, 0, 0, 0, 0, 71, 0, 78, 79,
+	80, 0, 117, 116, 0, 0, 0, 0, 0, 0,
+	0, 127, 128, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 129, 0, 0, 0, 130,
 }
 
-var yyPact = [...]int{
-	289, -1000, 289, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -23, 236, -32, -39, -42, 793, 766,
-	-1000, -1000, -1000, -1000, -1000, -1000, 970, 808, 808, 808,
-	808, 808, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 75, -1000, 183, -1000, -23, 808, 341, 808, -1000,
-	742, -1000, 808, 808, 808, 808, 808, 808, 808, 808,
-	808, 808, 808, 808, 808, 808, 808, 808, 808, 808,
-	808, 808, 808, 808, 808, 808, 808, 808, 808, 808,
-	808, 808, 700, -1000, -1000, -1000, -1000, 676, 30, -1000,
-	808, 71, -1000, -1000, 78, 647, 623, -23, 594, -1000,
-	91, 91, -1000, -1000, -1000, 957, 943, 927, 25, 25,
-	240, 240, 88, 88, 240, 240, 903, 874, 850, 850,
-	850, 850, 850, 850, 850, 850, 850, 850, 850, 570,
-	-1000, -44, 850, -1000, -37, -45, -1000, -23, 540, 516,
-	4, 289, 808, 1, 289, 994, -1000, 808, -1000, -37,
-	167, -41, -1000, -46, -35, -1000, 486, 808, 20, 808,
-	808, 808, 808, 808, 808, 808, 808, 808, 808, 808,
-	850, -1000, -1000, -43, -1000, -47, 808, 808, 462, 289,
-	850, 850, 850, 850, 850, 850, 850, 850, 850, 850,
-	850, -1000, -1000, -48, 433, 808, -1000, -1000, -37, 409,
-	-1000, 808, 380, -37, -1000,
+var yyPact = [...]int16{
+	1939, -32768, 1939, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	1022, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, 53, 2629, 157, 172, 172, 657, 160, 985,
+	158, 156, 152, 2182, 104, 98, 1855, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, 1022, 2611, 2611, 2611,
+	2611, 2611, 2611, 2611, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -62, -32768, -32768, 102, 172, 172, 1022,
+	-32768, 266, 2611, 2611, 2611, 2611, 2611, 2611, 2611, 2611,
+	2611, 2611, 2611, 2567, 90, 2523, 65, 148, 124, -32768,
+	558, -32768, 157, 2611, 227, 2611, 2611, 2611, -32768, 52,
+	1984, 434, 120, -32768, 32, -32768, 30, -32768, 2611, 2611,
+	2611, 2611, 2611, 2611, 2611, 2611, 2611, 2611, 2611, 2611,
+	2611, 2611, 2611, 2611, 2611, 2611, 2611, -32768, -32768, 140,
+	172, 88, 1022, -51, 1820, 137, 137, 137, 137, 137,
+	137, 1022, 767, 57, -32768, -32768, -45, -32768, -32768, -32768,
+	-32768, 1984, 1984, 1984, 1984, 1984, 1984, 1984, 1984, 1984,
+	1984, 1984, -32768, 17, -32768, 91, 14, 1785, -31, -32768,
+	157, -32768, 692, -32768, 2478, -14, 125, 835, 2331, 1022,
+	172, -32768, -43, -32768, -18, 1750, 100, 1692, 157, 1657,
+	1622, -32768, 2611, -32768, -32768, 220, 220, 137, 137, 137,
+	2273, 2124, 2089, 377, 377, 2661, 2661, 2420, 2420, 2661,
+	2661, 2054, 2019, 1564, 389, 2611, 553, -32768, 144, 1022,
+	2611, -32768, -53, -32768, 64, -32768, -32768, -32768, 1022, -32768,
+	1022, 91, 66, 91, -35, 102, 1506, 29, 2374, -32768,
+	2611, 117, 62, -1, -32768, -32768, 102, 1448, -32768, 640,
+	-32768, -47, -32768, -32768, 985, 2611, 2611, 25, 985, 99,
+	1984, 2611, 11, 2225, 1390, 2611, 2611, 2611, 2611, 2611,
+	2611, 2611, 2611, 2611, 2611, 2611, -32768, -44, 64, 137,
+	-32768, -32768, -32768, -32768, -42, 61, -32768, 91, -32768, -32768,
+	-59, -32768, -32768, 1984, -32768, 1332, 27, -32768, 2611, 835,
+	-32768, -32768, 24, -32768, 23, 172, -32768, 1297, 1239, 2611,
+	2611, 218, 253, 1984, 2611, 2611, 2611, 2611, 2611, 2611,
+	2611, 2611, 2611, 2611, 2611, 1181, -32768, -32768, 1984, 1984,
+	1984, 1984, 1984, 1984, 1984, 1984, 1984, 1984, 1984, -32768,
+	1022, -32768, -32768, 2478, -32768, -32768, 1123, -32768, -32768, -32768,
+	-32768, 19, 2611, 1065, 1030, 985, -32768, 187, -32768, 2611,
+	-49, 1984, 1984, 1984, 1984, 1984, 1984, 1984, 1984, 1984,
+	1984, 1984, -32768, 64, -32768, -32768, -32768, 901, 2611, 91,
+	-32768, -32768, -32768, -66, 798, 91, 843, -32768, 798, -32768,
+	729, -13, -32768, 2611, -32768, -38, -32768, 466, -32768, 91,
+	-32768,
 }
 
-var yyPgo = [...]int{
-	0, 115, 113, 94, 4, 112, 111, 102, 6, 1,
-	100, 2, 96, 90, 89, 88, 51, 0, 50, 45,
-	3, 85,
+var yyPgo = [...]int16{
+	0, 335, 333, 245, 302, 10, 329, 328, 5, 6,
+	314, 309, 307, 301, 297, 295, 15, 293, 13, 8,
+	292, 1, 258, 41, 259, 240, 236, 232, 291, 290,
+	288, 4, 2, 285, 277, 265, 263, 0, 261, 12,
+	3, 253, 246, 7, 9, 156,
 }
 
-var yyR1 = [...]int{
-	0, 1, 2, 2, 3, 3, 4, 4, 4, 4,
-	4, 4, 5, 5, 6, 6, 6, 6, 7, 7,
-	8, 8, 9, 9, 10, 10, 11, 11, 11, 11,
-	11, 11, 11, 12, 13, 13, 14, 14, 15, 15,
-	16, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	17, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	17, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	17, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	17, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	17, 17, 17, 17, 17, 18, 18, 19, 20, 20,
-	21,
+var yyR1 = [...]int8{
+	0, 1, 2, 2, 3, 3, 3, 3, 4, 4,
+	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
+	4, 4, 4, 4, 5, 6, 6, 17, 17, 19,
+	7, 7, 12, 13, 14, 14, 15, 15, 8, 8,
+	8, 8, 8, 10, 11, 11, 9, 9, 41, 41,
+	44, 44, 16, 16, 18, 18, 21, 21, 21, 22,
+	22, 23, 23, 23, 23, 23, 23, 23, 23, 23,
+	23, 23, 23, 23, 23, 23, 23, 23, 23, 23,
+	24, 25, 26, 26, 27, 28, 28, 29, 29, 30,
+	30, 31, 31, 32, 32, 32, 32, 33, 33, 34,
+	34, 35, 35, 36, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 38, 38, 20, 20, 39, 39, 42, 42,
+	43, 43, 40, 40, 45,
 }
 
-var yyR2 = [...]int{
+var yyR2 = [...]int8{
 	0, 1, 1, 2, 1, 1, 1, 1, 1, 1,
-	1, 1, 5, 6, 3, 5, 6, 7, 1, 3,
-	2, 4, 2, 3, 1, 2, 1, 1, 1, 1,
-	1, 1, 1, 5, 9, 12, 5, 7, 2, 3,
-	2, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	1, 1, 1, 1, 1, 1, 2, 2, 3, 4,
+	3, 3, 4, 5, 4, 1, 3, 1, 3, 1,
+	5, 6, 6, 6, 1, 3, 1, 2, 3, 5,
+	5, 4, 6, 7, 1, 3, 1, 2, 3, 4,
+	2, 3, 1, 3, 2, 3, 2, 3, 4, 1,
+	2, 1, 1, 2, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 2, 3, 3, 3, 3,
+	5, 7, 9, 12, 8, 5, 7, 6, 7, 1,
+	2, 4, 3, 0, 1, 2, 3, 2, 3, 2,
+	3, 2, 3, 2, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	2, 2, 2, 2, 3, 1, 1, 1, 1, 1,
-	1, 1, 1, 4, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 3, 4, 3, 1, 3,
-	1,
+	3, 3, 5, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 2, 2, 2, 2, 2, 2,
+	2, 2, 3, 4, 4, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 4, 6, 5, 5, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+	3, 5, 5, 5, 5, 5, 5, 5, 5, 5,
+	5, 5, 3, 4, 4, 5, 3, 2, 1, 3,
+	1, 1, 1, 3, 1,
 }
 
-var yyChk = [...]int{
-	-1000, -1, -2, -3, -11, -5, -6, -9, -12, -13,
-	-14, -15, -16, -4, 55, 10, 11, 12, 14, -17,
-	4, 5, 6, 7, 8, 9, -21, 16, 15, 24,
-	71, 53, -18, 47, 48, 50, 49, 51, 52, 46,
-	-3, -21, 56, -10, -11, -4, 53, 53, 53, 59,
-	-17, 59, 15, 16, 17, 18, 19, 20, 21, 22,
-	38, 37, 64, 26, 27, 63, 28, 65, 30, 31,
-	23, 39, 40, 41, 42, 43, 60, 61, 62, 44,
-	45, 57, 53, -17, -17, -17, -17, -17, 53, 59,
-	23, 57, 56, -11, -21, -17, -17, -4, -17, 59,
-	-17, -17, -17, -17, -17, -17, -17, -17, -17, -17,
-	-17, -17, -17, -17, -17, -17, -17, -17, -17, -17,
-	-17, -17, -17, -17, -17, -17, -17, -17, -17, -17,
-	54, -20, -17, 54, 54, -7, -8, -4, -17, -17,
-	58, 54, 59, -21, 54, 58, 54, 70, -9, 54,
-	70, -21, 59, 58, 23, -11, -17, 23, -11, 23,
-	39, 40, 41, 42, 43, 60, 61, 62, 44, 45,
-	-17, -9, -8, 57, 59, -19, 55, 59, -17, 13,
-	-17, -17, -17, -17, -17, -17, -17, -17, -17, -17,
-	-17, 58, 59, -20, -17, 59, -11, 56, 54, -17,
-	-9, 59, -17, 54, -9,
+var yyChk = [...]int16{
+	-32768, -1, -2, -3, -23, -7, -12, -13, -8, -10,
+	15, -21, -24, -25, -26, -27, -28, -29, -33, -34,
+	-35, -36, 2, -45, -4, 12, 13, 72, 16, 17,
+	18, 19, 24, 21, 22, 23, -37, 62, 4, 5,
+	6, 7, 8, 9, 10, 11, -5, 70, 60, 61,
+	29, 28, 37, 38, -38, -20, 63, 64, 65, 67,
+	66, 68, 69, 14, -3, -8, -4, 12, 13, 70,
+	76, 77, 36, 53, 54, 55, 56, 57, 79, 80,
+	81, 58, 59, 70, 74, 70, -45, -45, -45, 73,
+	-22, -23, -4, 70, -23, 70, 70, 70, 76, -40,
+	-37, -45, -4, 76, -45, 76, -45, 76, 28, 29,
+	30, 31, 32, 33, 34, 35, 52, 51, 84, 40,
+	41, 83, 42, 85, 44, 45, 82, 60, 61, 74,
+	78, -4, -5, -4, -37, -37, -37, -37, -37, -37,
+	-37, 84, 70, -45, -45, -45, -4, -24, -25, -26,
+	-27, -37, -37, -37, -37, -37, -37, -37, -37, -37,
+	-37, -37, 71, -40, 75, 71, -17, -37, -16, -19,
+	-4, -18, 70, 76, 36, -41, -44, 90, 74, 72,
+	72, 73, 2, -23, -45, -37, 16, -37, -4, -37,
+	-37, 76, 90, 76, 76, -37, -37, -37, -37, -37,
+	-37, -37, -37, -37, -37, -37, -37, -37, -37, -37,
+	-37, -37, -37, -37, -37, 77, -45, 85, -4, 90,
+	71, 71, -4, 71, -4, 71, -21, 71, 90, 71,
+	90, 71, -45, 71, -16, -4, -37, -39, 72, 76,
+	74, 36, 74, -11, -9, -45, -4, -37, 75, -15,
+	-8, -14, -45, 73, 71, 70, 76, -45, 71, 71,
+	-37, 77, 75, 77, -37, 36, 53, 54, 55, 56,
+	57, 79, 80, 81, 58, 59, 51, -6, -4, -37,
+	90, -19, -18, -21, -44, 74, -21, 71, 76, 76,
+	-42, 73, -43, -37, -39, -37, -39, 75, 36, 90,
+	-45, 75, 73, -8, 73, 90, -23, -37, -37, 36,
+	77, -23, 72, -37, 36, 53, 54, 55, 56, 57,
+	79, 80, 81, 58, 59, -37, 75, 75, -37, -37,
+	-37, -37, -37, -37, -37, -37, -37, -37, -37, 71,
+	90, -21, 73, 90, 75, 76, -37, -9, 76, 76,
+	-45, 71, 76, -37, -37, 20, 73, -30, -31, 25,
+	26, -37, -37, -37, -37, -37, -37, -37, -37, -37,
+	-37, -37, 75, -4, -43, 76, 76, -37, 76, 71,
+	-23, 73, -31, -40, 77, 71, -37, -21, 77, -32,
+	-22, 27, -21, 76, -32, 27, 76, -37, 76, 71,
+	-21,
 }
 
-var yyDef = [...]int{
-	0, -2, 1, 2, 4, 5, 26, 27, 28, 29,
-	30, 31, 32, 0, 0, 0, 0, 0, 0, 0,
-	6, 7, 8, 9, 10, 11, 76, 0, 0, 0,
-	0, 0, 75, 77, 78, 79, 80, 81, 82, 100,
-	3, 0, 22, 0, 24, 0, 0, 0, 0, 38,
-	0, 40, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+var yyDef = [...]int16{
+	0, -2, -2, 2, 4, 5, 6, 7, 61, 62,
+	0, 64, 65, 66, 67, 68, 69, 70, 71, 72,
+	73, 74, 0, 147, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 194, 8, 9,
+	10, 11, 12, 13, 14, 15, 0, 0, 0, 0,
+	0, 0, 0, 0, 145, 146, 148, 149, 150, 151,
+	152, 153, 154, 0, 3, 63, 0, 0, 0, 0,
+	75, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 16, 17, 56,
+	0, 59, 0, 0, 0, 0, 0, 0, 97, 0,
+	192, 147, 0, 99, 0, 101, 0, 103, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 70, 71, 72, 73, 0, 0, 14,
-	0, 0, 23, 25, 0, 0, 0, 0, 0, 39,
-	41, 42, 43, 44, 45, 46, 47, 48, 49, 50,
-	51, 52, 53, 54, 55, 56, 57, 58, 59, 60,
-	61, 62, 63, 64, 65, 66, 67, 68, 69, 0,
-	95, 0, 98, 74, 0, 0, 18, 0, 0, 0,
-	0, 0, 0, 0, 0, 83, 96, 0, 12, 0,
-	0, 20, 15, 0, 0, 33, 0, 0, 36, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	99, 13, 19, 0, 16, 0, 0, 0, 0, 0,
-	84, 85, 86, 87, 88, 89, 90, 91, 92, 93,
-	94, 21, 17, 0, 0, 0, 37, 97, 0, 0,
-	34, 0, 0, 0, 35,
+	0, 0, 0, 0, 0, 0, 0, 134, 135, 0,
+	0, 0, 0, 0, 0, 136, 137, 138, 139, 140,
+	141, 0, 0, 0, 16, 17, 0, 76, 77, 78,
+	79, 123, 124, 125, 126, 127, 128, 129, 130, 131,
+	132, 133, 182, 0, 20, 21, 0, 0, 0, 27,
+	29, 52, 0, 38, 0, 0, 0, 0, 0, 0,
+	0, 57, 0, 60, 0, 0, 0, 0, 0, 0,
+	0, 98, 0, 100, 102, 104, 105, 106, 107, 108,
+	109, 110, 111, 112, 113, 114, 115, 116, 117, 118,
+	119, 120, 121, 0, 0, 0, 170, 18, 0, 0,
+	0, 142, 0, 21, 29, 183, 184, 22, 0, 144,
+	0, 0, 54, 0, 0, 0, 0, 0, 0, 41,
+	0, 0, 0, 0, 44, 46, 0, 0, 50, 0,
+	36, 0, 34, 58, 0, 0, 0, 0, 0, 0,
+	193, 0, 155, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 19, 0, 25, 143,
+	24, 28, 53, 185, 55, 0, 30, 0, 39, 40,
+	0, 187, 188, 190, 191, 0, 0, 51, 0, 0,
+	47, 48, 0, 37, 0, 0, 80, 0, 0, 0,
+	0, 85, 0, 122, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 158, 157, 171, 172,
+	173, 174, 175, 176, 177, 178, 179, 180, 181, 23,
+	0, 31, 186, 0, 49, 42, 0, 45, 32, 33,
+	35, 0, 0, 0, 0, 0, 87, 0, 89, 0,
+	0, 159, 160, 161, 162, 163, 164, 165, 166, 167,
+	168, 169, 156, 26, 189, 43, 81, 0, 0, 0,
+	86, 88, 90, 0, -2, 0, 0, 84, -2, 92,
+	-2, 0, 82, 0, 91, 0, 95, 0, 96, 0,
+	83,
 }
 
-var yyTok1 = [...]int{
+var yyTok1 = [...]int8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 24, 3, 3, 3, 19, 20, 3,
-	53, 54, 17, 15, 70, 16, 3, 18, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 3, 59,
-	64, 23, 65, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 37, 3, 3, 3, 32, 33, 3,
+	70, 71, 30, 28, 90, 29, 78, 31, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 77, 76,
+	84, 36, 85, 82, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 57, 3, 58, 21, 3, 3, 3, 3, 3,
+	3, 74, 3, 75, 34, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 55, 22, 56, 71,
+	3, 3, 3, 72, 35, 73, 38,
 }
 
-var yyTok2 = [...]int{
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
-	12, 13, 14, 25, 26, 27, 28, 29, 30, 31,
-	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
-	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
-	52, 60, 61, 62, 63, 66, 67, 68, 69,
+	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
+	22, 23, 24, 25, 26, 27, 39, 40, 41, 42,
+	43, 44, 45, 46, 47, 48, 49, 50, 51, 52,
+	53, 54, 55, 56, 57, 58, 59, 60, 61, 62,
+	63, 64, 65, 66, 67, 68, 69, 79, 80, 81,
+	83, 86, 87, 88, 89,
 }
 
-var yyTok3 = [...]int{
+var yyTok3 = [...]int8{
 	0,
 }
 
@@ -653,7 +1204,7 @@ func yyNewParser() yyParser {
 	return &yyParserImpl{}
 }
 
-const yyFlag = -1000
+const yyFlag = -32768
 
 func yyTokname(c int) string {
 	if c >= 1 && c-1 < len(yyToknames) {
@@ -692,9 +1243,9 @@ func yyErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := yyPact[state]
+	base := int(yyPact[state])
 	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
-		if n := base + tok; n >= 0 && n < yyLast && yyChk[yyAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -704,13 +1255,13 @@ func yyErrorMessage(state, lookAhead int) string {
 
 	if yyDef[state] == -2 {
 		i := 0
-		for yyExca[i] != -1 || yyExca[i+1] != state {
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; yyExca[i] >= 0; i += 2 {
-			tok := yyExca[i]
+			tok := int(yyExca[i])
 			if tok < TOKSTART || yyExca[i+1] == 0 {
 				continue
 			}
@@ -741,30 +1292,30 @@ func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		token = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			token = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		token = yyTok3[i+0]
+		token = int(yyTok3[i+0])
 		if token == char {
-			token = yyTok3[i+1]
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = yyTok2[1] /* unknown char */
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
@@ -819,7 +1370,7 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
@@ -830,8 +1381,8 @@ yynewstate:
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yytoken { /* valid shift */
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
 		yyrcvr.char = -1
 		yytoken = -1
 		yyVAL = yyrcvr.lval
@@ -844,7 +1395,7 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
 		if yyrcvr.char < 0 {
 			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
@@ -853,18 +1404,18 @@ yydefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
+			yyn = int(yyExca[xi+0])
 			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -886,10 +1437,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -925,7 +1476,7 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
 	// yyp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if yyp+1 >= len(yyS) {
@@ -936,16 +1487,16 @@ yydefault:
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -953,713 +1504,1465 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:99
+//line parser.y:134
 		{
 			yyVAL.Program = ast.Program{Statements: yyDollar[1].DeclList}
 			yylex.(*Lexer).result = yyVAL.Program
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:103
+//line parser.y:138
 		{
 			yyVAL.DeclList = []ast.Statement{yyDollar[1].Decl}
 		}
 	case 3:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:104
+//line parser.y:139
 		{
 			yyVAL.DeclList = append(yyDollar[1].DeclList, yyDollar[2].Decl)
 		}
 	case 4:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:108
+//line parser.y:143
 		{
 			yyVAL.Decl = yyDollar[1].Stmt
 		}
 	case 5:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:109
+//line parser.y:144
 		{
 			yyVAL.Decl = yyDollar[1].FuncDecl
 		}
 	case 6:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:113
+//line parser.y:145
 		{
-			yyVAL.Type = ast.Type{Value: yyDollar[1].token.Literal}
+			yyVAL.Decl = yyDollar[1].StructDecl
 		}
 	case 7:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:114
+//line parser.y:146
 		{
-			yyVAL.Type = ast.Type{Value: yyDollar[1].token.Literal}
+			yyVAL.Decl = yyDollar[1].EnumDecl
 		}
 	case 8:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:115
+//line parser.y:150
 		{
-			yyVAL.Type = ast.Type{Value: yyDollar[1].token.Literal}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 9:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:116
+//line parser.y:151
 		{
-			yyVAL.Type = ast.Type{Value: yyDollar[1].token.Literal}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 10:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:117
+//line parser.y:152
 		{
-			yyVAL.Type = ast.Type{Value: yyDollar[1].token.Literal}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 11:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:118
+//line parser.y:153
 		{
-			yyVAL.Type = ast.Type{Value: yyDollar[1].token.Literal}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 12:
-		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:122
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:154
 		{
-			yyVAL.FuncDecl = ast.FuncDecl{
-				Type:       yyDollar[1].Type,
-				Ident:      yyDollar[2].Id,
-				Parameters: make([]ast.Param, 0),
-				Body:       yyDollar[5].Block,
-			}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 13:
-		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:130
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:155
 		{
-			yyVAL.FuncDecl = ast.FuncDecl{
-				Type:       yyDollar[1].Type,
-				Ident:      yyDollar[2].Id,
-				Parameters: yyDollar[4].ParamList,
-				Body:       yyDollar[6].Block,
-			}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 14:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:141
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:156
 		{
-			yyVAL.VarDecl = ast.VarDecl{
-				Type:        yyDollar[1].Type,
-				Ident:       yyDollar[2].Id,
-				Initialized: false,
-			}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 15:
-		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:148
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:157
 		{
-			yyVAL.VarDecl = ast.VarDecl{
-				Type:        yyDollar[1].Type,
-				Ident:       yyDollar[2].Id,
-				Value:       yyDollar[4].Expr,
-				Initialized: true,
-			}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
 	case 16:
-		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:156
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:158
 		{
-			yyVAL.VarDecl = ast.VarDecl{
-				Type:        ast.Type{Value: yyDollar[1].Type.Value + "arr"},
-				Ident:       yyDollar[2].Id,
-				Value:       yyDollar[4].Expr,
-				Initialized: false,
-			}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: "struct " + yyDollar[2].Id.Name}
 		}
 	case 17:
-		yyDollar = yyS[yypt-7 : yypt+1]
-//line parser.y:164
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:159
 		{
-			yyVAL.VarDecl = ast.VarDecl{
-				Type:        ast.Type{Value: yyDollar[1].Type.Value + "arr"},
-				Ident:       yyDollar[2].Id,
-				Value:       yyDollar[6].Array,
-				Initialized: true,
-			}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: "enum " + yyDollar[2].Id.Name}
 		}
 	case 18:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:175
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:160
 		{
-			yyVAL.ParamList = []ast.Param{yyDollar[1].Param}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].Type.Pos, Value: yyDollar[1].Type.Value + yyDollar[2].Type.Value + ">"}
 		}
 	case 19:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:176
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:161
 		{
-			yyVAL.ParamList = append(yyDollar[1].ParamList, yyDollar[3].Param)
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].Type.Pos, Value: yyDollar[1].Type.Value + yyDollar[2].Type.Value + yyDollar[3].Type.Value + ">>"}
 		}
 	case 20:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:180
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:164
 		{
-			yyVAL.Param = ast.Param{Type: yyDollar[1].Type, Ident: yyDollar[2].Id, Array: false}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].Type.Pos, Value: yyDollar[1].Type.Value + "arr"}
 		}
 	case 21:
-		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:181
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:165
 		{
-			yyVAL.Param = ast.Param{Type: yyDollar[1].Type, Ident: yyDollar[2].Id, Array: true}
+			yyVAL.Type = ast.FuncType(yyDollar[1].Type, make([]ast.Param, 0))
 		}
 	case 22:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:185
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:166
 		{
-			yyVAL.Block = ast.Block{Statements: make([]ast.Statement, 0)}
+			yyVAL.Type = ast.FuncType(yyDollar[1].Type, yyDollar[3].ParamList)
 		}
 	case 23:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:188
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:167
 		{
-			yyVAL.Block = ast.Block{Statements: yyDollar[2].StmtList}
+			yyVAL.Type = ast.TupleType(append([]ast.Type{yyDollar[2].Type}, yyDollar[4].Types...))
+			yyVAL.Type.Pos = yyDollar[1].token.Pos
 		}
 	case 24:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:194
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:174
 		{
-			yyVAL.StmtList = []ast.Statement{yyDollar[1].Stmt}
+			yyVAL.Type = ast.Type{Pos: yyDollar[1].token.Pos, Value: "map<" + yyDollar[3].Type.Value + ","}
 		}
 	case 25:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:195
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:178
 		{
-			yyVAL.StmtList = append(yyDollar[1].StmtList, yyDollar[2].Stmt)
+			yyVAL.Types = []ast.Type{yyDollar[1].Type}
 		}
 	case 26:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:199
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:179
 		{
-			yyVAL.Stmt = yyDollar[1].VarDecl
+			yyVAL.Types = append(yyDollar[1].Types, yyDollar[3].Type)
 		}
 	case 27:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:200
+//line parser.y:183
 		{
-			yyVAL.Stmt = yyDollar[1].Block
+			yyVAL.ParamList = []ast.Param{yyDollar[1].Param}
 		}
 	case 28:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:201
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:184
 		{
-			yyVAL.Stmt = yyDollar[1].While
+			yyVAL.ParamList = append(yyDollar[1].ParamList, yyDollar[3].Param)
 		}
 	case 29:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:202
+//line parser.y:188
 		{
-			yyVAL.Stmt = yyDollar[1].For
+			yyVAL.Param = ast.Param{Type: yyDollar[1].Type, Array: false}
 		}
 	case 30:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:203
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:192
 		{
-			yyVAL.Stmt = yyDollar[1].IfElse
+			yyVAL.FuncDecl = ast.FuncDecl{
+				Pos:        yyDollar[1].Type.Pos,
+				Type:       yyDollar[1].Type,
+				Ident:      yyDollar[2].Id,
+				Parameters: make([]ast.Param, 0),
+				Body:       yyDollar[5].Block,
+			}
 		}
 	case 31:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:204
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:201
 		{
-			yyVAL.Stmt = yyDollar[1].Return
+			yyVAL.FuncDecl = ast.FuncDecl{
+				Pos:        yyDollar[1].Type.Pos,
+				Type:       yyDollar[1].Type,
+				Ident:      yyDollar[2].Id,
+				Parameters: yyDollar[4].ParamList,
+				Body:       yyDollar[6].Block,
+			}
 		}
 	case 32:
-		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:205
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:213
 		{
-			yyVAL.Stmt = yyDollar[1].ExprStmt
+			yyVAL.StructDecl = ast.StructDecl{
+				Pos:    yyDollar[1].token.Pos,
+				Ident:  yyDollar[2].Id,
+				Fields: yyDollar[4].FieldList,
+			}
 		}
 	case 33:
-		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:209
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:223
 		{
-			yyVAL.While = ast.While{
-				Condition: yyDollar[3].Expr,
-				Body:      yyDollar[5].Stmt,
+			yyVAL.EnumDecl = ast.EnumDecl{
+				Pos:     yyDollar[1].token.Pos,
+				Ident:   yyDollar[2].Id,
+				Members: yyDollar[4].IdList,
 			}
 		}
 	case 34:
-		yyDollar = yyS[yypt-9 : yypt+1]
-//line parser.y:218
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:233
 		{
-			yyVAL.For = ast.For{
-				Init:      yyDollar[3].Expr,
-				Condition: yyDollar[5].Expr,
-				Increment: yyDollar[7].Expr,
-				Body:      yyDollar[9].Block,
-			}
+			yyVAL.IdList = []ast.Identifier{yyDollar[1].Id}
 		}
 	case 35:
-		yyDollar = yyS[yypt-12 : yypt+1]
-//line parser.y:226
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:234
 		{
-			yyVAL.For = ast.For{
-				VarDecl:   true,
-				Type:      yyDollar[3].Type,
-				Ident:     yyDollar[4].Id,
-				Value:     yyDollar[6].Expr,
-				Condition: yyDollar[8].Expr,
-				Increment: yyDollar[10].Expr,
-				Body:      yyDollar[12].Block,
-			}
+			yyVAL.IdList = append(yyDollar[1].IdList, yyDollar[3].Id)
 		}
 	case 36:
-		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:240
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:238
 		{
-			yyVAL.IfElse = ast.IfElse{
-				Condition:      yyDollar[3].Expr,
-				Consequence:    yyDollar[5].Stmt,
-				HasAlternative: false,
-			}
+			yyVAL.FieldList = []ast.VarDecl{yyDollar[1].VarDecl}
 		}
 	case 37:
-		yyDollar = yyS[yypt-7 : yypt+1]
-//line parser.y:247
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:239
 		{
-			yyVAL.IfElse = ast.IfElse{
-				Condition:      yyDollar[3].Expr,
-				Consequence:    yyDollar[5].Stmt,
-				Alternative:    yyDollar[7].Stmt,
-				HasAlternative: true,
-			}
+			yyVAL.FieldList = append(yyDollar[1].FieldList, yyDollar[2].VarDecl)
 		}
 	case 38:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:258
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:243
 		{
-			yyVAL.Return = ast.Return{Void: true}
+			yyVAL.VarDecl = ast.VarDecl{
+				Pos:         yyDollar[1].Type.Pos,
+				Type:        yyDollar[1].Type,
+				Ident:       yyDollar[2].Id,
+				Initialized: false,
+			}
 		}
 	case 39:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:259
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:251
 		{
-			yyVAL.Return = ast.Return{Value: yyDollar[2].Expr, Void: false}
+			yyVAL.VarDecl = ast.VarDecl{
+				Pos:         yyDollar[1].Type.Pos,
+				Type:        yyDollar[1].Type,
+				Ident:       yyDollar[2].Id,
+				Value:       yyDollar[4].Expr,
+				Initialized: true,
+			}
 		}
 	case 40:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:263
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:260
 		{
-			yyVAL.ExprStmt = ast.ExprStmt{Expression: yyDollar[1].Expr}
+			yyVAL.VarDecl = ast.VarDecl{
+				Pos:         yyDollar[1].Type.Pos,
+				Type:        yyDollar[1].Type,
+				Ident:       yyDollar[2].Id,
+				Value:       yyDollar[4].Array,
+				Initialized: true,
+			}
 		}
 	case 41:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:267
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:269
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "+", Right: yyDollar[3].Expr}
+			yyVAL.VarDecl = ast.VarDecl{
+				Pos:         yyDollar[1].Type.Pos,
+				Type:        ast.Type{Pos: yyDollar[1].Type.Pos, Value: yyDollar[1].Type.Value + strings.Repeat("arr", len(yyDollar[3].ExprList))},
+				Ident:       yyDollar[2].Id,
+				Dimensions:  yyDollar[3].ExprList,
+				Initialized: false,
+			}
 		}
 	case 42:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:268
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:278
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "-", Right: yyDollar[3].Expr}
+			yyVAL.VarDecl = ast.VarDecl{
+				Pos:         yyDollar[1].Type.Pos,
+				Type:        ast.Type{Pos: yyDollar[1].Type.Pos, Value: yyDollar[1].Type.Value + strings.Repeat("arr", yyDollar[3].Depth)},
+				Ident:       yyDollar[2].Id,
+				Value:       yyDollar[5].Array,
+				Initialized: true,
+			}
 		}
 	case 43:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:269
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:290
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "*", Right: yyDollar[3].Expr}
+			first := ast.VarDecl{Pos: yyDollar[1].Type.Pos, Type: yyDollar[1].Type, Ident: yyDollar[2].Id}
+			yyVAL.TupleDecl = ast.TupleDecl{
+				Pos:   yyDollar[1].Type.Pos,
+				Decls: append([]ast.VarDecl{first}, yyDollar[4].TargetList...),
+				Value: yyDollar[6].Expr,
+			}
+			for i := 1; i < len(yyVAL.TupleDecl.Decls); i++ {
+				if yyVAL.TupleDecl.Decls[i].Type.Value == "" {
+					yyVAL.TupleDecl.Decls[i].Type = yyVAL.TupleDecl.Decls[i-1].Type
+				}
+			}
 		}
 	case 44:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:270
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:306
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "/", Right: yyDollar[3].Expr}
+			yyVAL.TargetList = []ast.VarDecl{yyDollar[1].VarDecl}
 		}
 	case 45:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:271
+//line parser.y:307
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "%", Right: yyDollar[3].Expr}
+			yyVAL.TargetList = append(yyDollar[1].TargetList, yyDollar[3].VarDecl)
 		}
 	case 46:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:272
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:311
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "&", Right: yyDollar[3].Expr}
+			yyVAL.VarDecl = ast.VarDecl{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id}
 		}
 	case 47:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:273
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:312
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "^", Right: yyDollar[3].Expr}
+			yyVAL.VarDecl = ast.VarDecl{Pos: yyDollar[1].Type.Pos, Type: yyDollar[1].Type, Ident: yyDollar[2].Id}
 		}
 	case 48:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:274
+//line parser.y:316
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "|", Right: yyDollar[3].Expr}
+			yyVAL.ExprList = []ast.Expression{yyDollar[2].Expr}
 		}
 	case 49:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:275
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:317
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "<<", Right: yyDollar[3].Expr}
+			yyVAL.ExprList = append(yyDollar[1].ExprList, yyDollar[3].Expr)
 		}
 	case 50:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:276
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:321
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: ">>", Right: yyDollar[3].Expr}
+			yyVAL.Depth = 1
 		}
 	case 51:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:277
+//line parser.y:322
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "<", Right: yyDollar[3].Expr}
+			yyVAL.Depth = yyDollar[1].Depth + 1
 		}
 	case 52:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:278
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:326
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "<=", Right: yyDollar[3].Expr}
+			yyVAL.ParamList = []ast.Param{yyDollar[1].Param}
 		}
 	case 53:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:279
+//line parser.y:327
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "==", Right: yyDollar[3].Expr}
+			yyVAL.ParamList = append(yyDollar[1].ParamList, yyDollar[3].Param)
 		}
 	case 54:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:280
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:331
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "!=", Right: yyDollar[3].Expr}
+			yyVAL.Param = ast.Param{Type: yyDollar[1].Type, Ident: yyDollar[2].Id, Array: false}
 		}
 	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:281
+//line parser.y:332
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: ">=", Right: yyDollar[3].Expr}
+			yyVAL.Param = ast.Param{
+				Type:  ast.Type{Pos: yyDollar[1].Type.Pos, Value: yyDollar[1].Type.Value + strings.Repeat("arr", yyDollar[3].Depth-1)},
+				Ident: yyDollar[2].Id,
+				Array: true,
+			}
 		}
 	case 56:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:282
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:342
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: ">", Right: yyDollar[3].Expr}
+			yyVAL.Block = ast.Block{Pos: yyDollar[1].token.Pos, Statements: make([]ast.Statement, 0)}
 		}
 	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:283
+//line parser.y:345
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "&&", Right: yyDollar[3].Expr}
+			yyVAL.Block = ast.Block{Pos: yyDollar[1].token.Pos, Statements: yyDollar[2].StmtList}
 		}
 	case 58:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:284
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:348
 		{
-			yyVAL.Expr = ast.InfixExpr{Left: yyDollar[1].Expr, Op: "||", Right: yyDollar[3].Expr}
+			yyVAL.Block = ast.Block{Pos: yyDollar[1].token.Pos, Statements: yyDollar[2].StmtList}
 		}
 	case 59:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:285
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:354
 		{
-			yyVAL.Expr = ast.Assign{Ident: yyDollar[1].Id, Value: yyDollar[3].Expr}
+			yyVAL.StmtList = []ast.Statement{yyDollar[1].Stmt}
 		}
 	case 60:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:286
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:355
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "+=", Value: yyDollar[3].Expr}
+			yyVAL.StmtList = append(yyDollar[1].StmtList, yyDollar[2].Stmt)
 		}
 	case 61:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:287
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:359
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "-=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].VarDecl
 		}
 	case 62:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:288
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:360
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "*=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].TupleDecl
 		}
 	case 63:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:289
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:361
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "/=", Value: yyDollar[3].Expr}
+			if !yyDollar[2].VarDecl.Initialized {
+				yylex.Error("const " + yyDollar[2].VarDecl.Ident.Name + " must be initialized")
+			}
+			yyDollar[2].VarDecl.Const = true
+			yyDollar[2].VarDecl.Pos = yyDollar[1].token.Pos
+			yyVAL.Stmt = yyDollar[2].VarDecl
 		}
 	case 64:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:290
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:369
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "%=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].Block
 		}
 	case 65:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:291
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:370
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "&=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].While
 		}
 	case 66:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:292
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:371
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "^=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].DoWhile
 		}
 	case 67:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:293
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:372
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "|=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].For
 		}
 	case 68:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:294
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:373
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: "<<=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].ForEach
 		}
 	case 69:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:295
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:374
 		{
-			yyVAL.Expr = ast.AssignExpr{Ident: yyDollar[1].Id, Op: ">>=", Value: yyDollar[3].Expr}
+			yyVAL.Stmt = yyDollar[1].IfElse
 		}
 	case 70:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:296
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:375
 		{
-			yyVAL.Expr = ast.PrefixExpr{Op: "-", Right: yyDollar[2].Expr}
+			yyVAL.Stmt = yyDollar[1].Switch
 		}
 	case 71:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:297
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:376
 		{
-			yyVAL.Expr = ast.PrefixExpr{Op: "+", Right: yyDollar[2].Expr}
+			yyVAL.Stmt = yyDollar[1].Return
 		}
 	case 72:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:298
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:377
 		{
-			yyVAL.Expr = ast.PrefixExpr{Op: "!", Right: yyDollar[2].Expr}
+			yyVAL.Stmt = yyDollar[1].Break
 		}
 	case 73:
-		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:299
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:378
 		{
-			yyVAL.Expr = ast.PrefixExpr{Op: "~", Right: yyDollar[2].Expr}
+			yyVAL.Stmt = yyDollar[1].Continue
 		}
 	case 74:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:379
+		{
+			yyVAL.Stmt = yyDollar[1].ExprStmt
+		}
+	case 75:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:380
+		{
+			// Stands in for a statement that failed to parse, so that parsing
+			// resumes at the next one and reports any further syntax errors.
+			yyVAL.Stmt = ast.Block{Pos: yyDollar[2].token.Pos}
+		}
+	case 76:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:385
+		{
+			yyDollar[3].While.Pos = yyDollar[1].Id.Pos
+			yyDollar[3].While.Label = yyDollar[1].Id
+			yyVAL.Stmt = yyDollar[3].While
+		}
+	case 77:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:390
+		{
+			yyDollar[3].DoWhile.Pos = yyDollar[1].Id.Pos
+			yyDollar[3].DoWhile.Label = yyDollar[1].Id
+			yyVAL.Stmt = yyDollar[3].DoWhile
+		}
+	case 78:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:300
+//line parser.y:395
+		{
+			yyDollar[3].For.Pos = yyDollar[1].Id.Pos
+			yyDollar[3].For.Label = yyDollar[1].Id
+			yyVAL.Stmt = yyDollar[3].For
+		}
+	case 79:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:400
+		{
+			yyDollar[3].ForEach.Pos = yyDollar[1].Id.Pos
+			yyDollar[3].ForEach.Label = yyDollar[1].Id
+			yyVAL.Stmt = yyDollar[3].ForEach
+		}
+	case 80:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:408
+		{
+			yyVAL.While = ast.While{
+				Pos:       yyDollar[1].token.Pos,
+				Condition: yyDollar[3].Expr,
+				Body:      yyDollar[5].Stmt,
+			}
+		}
+	case 81:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:418
+		{
+			yyVAL.DoWhile = ast.DoWhile{
+				Pos:       yyDollar[1].token.Pos,
+				Body:      yyDollar[2].Stmt,
+				Condition: yyDollar[5].Expr,
+			}
+		}
+	case 82:
+		yyDollar = yyS[yypt-9 : yypt+1]
+//line parser.y:428
+		{
+			yyVAL.For = ast.For{
+				Pos:       yyDollar[1].token.Pos,
+				Init:      yyDollar[3].Expr,
+				Condition: yyDollar[5].Expr,
+				Increment: yyDollar[7].Expr,
+				Body:      yyDollar[9].Block,
+			}
+		}
+	case 83:
+		yyDollar = yyS[yypt-12 : yypt+1]
+//line parser.y:437
+		{
+			yyVAL.For = ast.For{
+				Pos:       yyDollar[1].token.Pos,
+				VarDecl:   true,
+				Type:      yyDollar[3].Type,
+				Ident:     yyDollar[4].Id,
+				Value:     yyDollar[6].Expr,
+				Condition: yyDollar[8].Expr,
+				Increment: yyDollar[10].Expr,
+				Body:      yyDollar[12].Block,
+			}
+		}
+	case 84:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line parser.y:452
+		{
+			yyVAL.ForEach = ast.ForEach{
+				Pos:        yyDollar[1].token.Pos,
+				Type:       yyDollar[3].Type,
+				Ident:      yyDollar[4].Id,
+				Collection: yyDollar[6].Expr,
+				Body:       yyDollar[8].Block,
+			}
+		}
+	case 85:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:464
+		{
+			yyVAL.IfElse = ast.IfElse{
+				Pos:            yyDollar[1].token.Pos,
+				Condition:      yyDollar[3].Expr,
+				Consequence:    yyDollar[5].Stmt,
+				HasAlternative: false,
+			}
+		}
+	case 86:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:472
+		{
+			yyVAL.IfElse = ast.IfElse{
+				Pos:            yyDollar[1].token.Pos,
+				Condition:      yyDollar[3].Expr,
+				Consequence:    yyDollar[5].Stmt,
+				Alternative:    yyDollar[7].Stmt,
+				HasAlternative: true,
+			}
+		}
+	case 87:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:484
+		{
+			yyVAL.Switch = ast.Switch{
+				Pos:   yyDollar[1].token.Pos,
+				Value: yyDollar[3].Expr,
+				Cases: make([]ast.Case, 0),
+			}
+		}
+	case 88:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:491
+		{
+			if last := yyDollar[6].CaseList[len(yyDollar[6].CaseList)-1]; last.Fallthrough {
+				yylex.(*Lexer).errorAt(last.Pos, "cannot fallthrough final case in switch")
+			}
+			yyVAL.Switch = ast.Switch{
+				Pos:   yyDollar[1].token.Pos,
+				Value: yyDollar[3].Expr,
+				Cases: yyDollar[6].CaseList,
+			}
+		}
+	case 89:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:504
+		{
+			yyVAL.CaseList = []ast.Case{yyDollar[1].Case}
+		}
+	case 90:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:505
+		{
+			yyVAL.CaseList = append(yyDollar[1].CaseList, yyDollar[2].Case)
+		}
+	case 91:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:509
+		{
+			yyVAL.Case = yyDollar[4].Case
+			yyVAL.Case.Pos = yyDollar[1].token.Pos
+			yyVAL.Case.Body.Pos = yyDollar[1].token.Pos
+			yyVAL.Case.Values = yyDollar[2].ExprList
+		}
+	case 92:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:515
+		{
+			yyVAL.Case = yyDollar[3].Case
+			yyVAL.Case.Pos = yyDollar[1].token.Pos
+			yyVAL.Case.Body.Pos = yyDollar[1].token.Pos
+			yyVAL.Case.Default = true
+		}
+	case 93:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line parser.y:524
+		{
+			yyVAL.Case = ast.Case{Body: ast.Block{Statements: make([]ast.Statement, 0)}}
+		}
+	case 94:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:527
+		{
+			yyVAL.Case = ast.Case{Body: ast.Block{Statements: yyDollar[1].StmtList}}
+		}
+	case 95:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:530
+		{
+			yyVAL.Case = ast.Case{
+				Body:        ast.Block{Statements: make([]ast.Statement, 0)},
+				Fallthrough: true,
+			}
+		}
+	case 96:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:536
+		{
+			yyVAL.Case = ast.Case{
+				Body:        ast.Block{Statements: yyDollar[1].StmtList},
+				Fallthrough: true,
+			}
+		}
+	case 97:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:545
+		{
+			yyVAL.Return = ast.Return{Pos: yyDollar[1].token.Pos, Void: true}
+		}
+	case 98:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:546
+		{
+			if len(yyDollar[2].ExprList) == 1 {
+				yyVAL.Return = ast.Return{Pos: yyDollar[1].token.Pos, Value: yyDollar[2].ExprList[0], Void: false}
+			} else {
+				yyVAL.Return = ast.Return{
+					Pos:   yyDollar[1].token.Pos,
+					Value: ast.Tuple{Pos: yyDollar[2].ExprList[0].Position(), Elements: yyDollar[2].ExprList},
+					Void:  false,
+				}
+			}
+		}
+	case 99:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:560
+		{
+			yyVAL.Break = ast.Break{Pos: yyDollar[1].token.Pos}
+		}
+	case 100:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:561
+		{
+			yyVAL.Break = ast.Break{Pos: yyDollar[1].token.Pos, Label: yyDollar[2].Id}
+		}
+	case 101:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:565
+		{
+			yyVAL.Continue = ast.Continue{Pos: yyDollar[1].token.Pos}
+		}
+	case 102:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:566
+		{
+			yyVAL.Continue = ast.Continue{Pos: yyDollar[1].token.Pos, Label: yyDollar[2].Id}
+		}
+	case 103:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:570
+		{
+			yyVAL.ExprStmt = ast.ExprStmt{Pos: yyDollar[1].Expr.Position(), Expression: yyDollar[1].Expr}
+		}
+	case 104:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:574
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "+", Right: yyDollar[3].Expr})
+		}
+	case 105:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:575
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "-", Right: yyDollar[3].Expr})
+		}
+	case 106:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:576
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "*", Right: yyDollar[3].Expr})
+		}
+	case 107:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:577
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "/", Right: yyDollar[3].Expr})
+		}
+	case 108:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:578
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "%", Right: yyDollar[3].Expr})
+		}
+	case 109:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:579
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "&", Right: yyDollar[3].Expr})
+		}
+	case 110:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:580
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "^", Right: yyDollar[3].Expr})
+		}
+	case 111:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:581
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "|", Right: yyDollar[3].Expr})
+		}
+	case 112:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:582
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "<<", Right: yyDollar[3].Expr})
+		}
+	case 113:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:583
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: ">>", Right: yyDollar[3].Expr})
+		}
+	case 114:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:584
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "<", Right: yyDollar[3].Expr})
+		}
+	case 115:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:585
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "<=", Right: yyDollar[3].Expr})
+		}
+	case 116:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:586
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "==", Right: yyDollar[3].Expr})
+		}
+	case 117:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:587
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "!=", Right: yyDollar[3].Expr})
+		}
+	case 118:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:588
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: ">=", Right: yyDollar[3].Expr})
+		}
+	case 119:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:589
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: ">", Right: yyDollar[3].Expr})
+		}
+	case 120:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:590
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "&&", Right: yyDollar[3].Expr})
+		}
+	case 121:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:591
+		{
+			yyVAL.Expr = fold(ast.InfixExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Op: "||", Right: yyDollar[3].Expr})
+		}
+	case 122:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:592
+		{
+			yyVAL.Expr = ast.Ternary{
+				Pos:         yyDollar[1].Expr.Position(),
+				Condition:   yyDollar[1].Expr,
+				Consequence: yyDollar[3].Expr,
+				Alternative: yyDollar[5].Expr,
+			}
+		}
+	case 123:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:600
+		{
+			yyVAL.Expr = ast.Assign{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Value: yyDollar[3].Expr}
+		}
+	case 124:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:601
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "+=", Value: yyDollar[3].Expr}
+		}
+	case 125:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:602
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "-=", Value: yyDollar[3].Expr}
+		}
+	case 126:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:603
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "*=", Value: yyDollar[3].Expr}
+		}
+	case 127:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:604
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "/=", Value: yyDollar[3].Expr}
+		}
+	case 128:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:605
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "%=", Value: yyDollar[3].Expr}
+		}
+	case 129:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:606
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "&=", Value: yyDollar[3].Expr}
+		}
+	case 130:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:607
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "^=", Value: yyDollar[3].Expr}
+		}
+	case 131:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:608
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "|=", Value: yyDollar[3].Expr}
+		}
+	case 132:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:609
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: "<<=", Value: yyDollar[3].Expr}
+		}
+	case 133:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:610
+		{
+			yyVAL.Expr = ast.AssignExpr{Pos: yyDollar[1].Id.Pos, Ident: yyDollar[1].Id, Op: ">>=", Value: yyDollar[3].Expr}
+		}
+	case 134:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:611
+		{
+			yyVAL.Expr = incDec(yylex, yyDollar[1].Expr.Position(), yyDollar[1].Expr, "++", false)
+		}
+	case 135:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:612
+		{
+			yyVAL.Expr = incDec(yylex, yyDollar[1].Expr.Position(), yyDollar[1].Expr, "--", false)
+		}
+	case 136:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:613
+		{
+			yyVAL.Expr = incDec(yylex, yyDollar[1].token.Pos, yyDollar[2].Expr, "++", true)
+		}
+	case 137:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:614
+		{
+			yyVAL.Expr = incDec(yylex, yyDollar[1].token.Pos, yyDollar[2].Expr, "--", true)
+		}
+	case 138:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:615
+		{
+			yyVAL.Expr = fold(ast.PrefixExpr{Pos: yyDollar[1].token.Pos, Op: "-", Right: yyDollar[2].Expr})
+		}
+	case 139:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:616
+		{
+			yyVAL.Expr = ast.PrefixExpr{Pos: yyDollar[1].token.Pos, Op: "+", Right: yyDollar[2].Expr}
+		}
+	case 140:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:617
+		{
+			yyVAL.Expr = fold(ast.PrefixExpr{Pos: yyDollar[1].token.Pos, Op: "!", Right: yyDollar[2].Expr})
+		}
+	case 141:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:618
+		{
+			yyVAL.Expr = fold(ast.PrefixExpr{Pos: yyDollar[1].token.Pos, Op: "~", Right: yyDollar[2].Expr})
+		}
+	case 142:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:619
 		{
 			yyVAL.Expr = yyDollar[2].Expr
 		}
-	case 75:
+	case 143:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:620
+		{
+			yyVAL.Expr = ast.Cast{Pos: yyDollar[1].token.Pos, Type: yyDollar[2].Type, Value: yyDollar[4].Expr}
+		}
+	case 144:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:621
+		{
+			yyVAL.Expr = ast.Cast{Pos: yyDollar[1].Type.Pos, Type: yyDollar[1].Type, Value: yyDollar[3].Expr}
+		}
+	case 145:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:301
+//line parser.y:622
 		{
 			yyVAL.Expr = yyDollar[1].Call
 		}
-	case 76:
+	case 146:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:302
+//line parser.y:623
+		{
+			yyVAL.Expr = yyDollar[1].Lambda
+		}
+	case 147:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:624
 		{
 			yyVAL.Expr = yyDollar[1].Id
 		}
-	case 77:
+	case 148:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:303
+//line parser.y:625
 		{
-			yyVAL.Expr = ast.CharCon{Value: yyDollar[1].token.Literal}
+			yyVAL.Expr = ast.CharCon{Pos: yyDollar[1].token.Pos, Value: rune(yyDollar[1].token.Int)}
 		}
-	case 78:
+	case 149:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:304
+//line parser.y:626
 		{
-			yyVAL.Expr = ast.IntCon{Value: yyDollar[1].token.Int}
+			yyVAL.Expr = ast.IntCon{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Int}
 		}
-	case 79:
+	case 150:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:305
+//line parser.y:627
 		{
-			yyVAL.Expr = ast.FloatCon{Value: yyDollar[1].token.Float}
+			yyVAL.Expr = ast.BigIntCon{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
-	case 80:
+	case 151:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:306
+//line parser.y:628
 		{
-			yyVAL.Expr = ast.StringCon{Value: yyDollar[1].token.Literal}
+			yyVAL.Expr = ast.FloatCon{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Float}
 		}
-	case 81:
+	case 152:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:307
+//line parser.y:629
 		{
-			yyVAL.Expr = ast.Bool{Value: yyDollar[1].token.Bool}
+			yyVAL.Expr = ast.StringCon{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Literal}
 		}
-	case 82:
+	case 153:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:308
+//line parser.y:630
 		{
-			yyVAL.Expr = ast.Bool{Value: yyDollar[1].token.Bool}
+			yyVAL.Expr = ast.Bool{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Bool}
 		}
-	case 83:
+	case 154:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:631
+		{
+			yyVAL.Expr = ast.Bool{Pos: yyDollar[1].token.Pos, Value: yyDollar[1].token.Bool}
+		}
+	case 155:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:309
+//line parser.y:632
 		{
-			yyVAL.Expr = ast.IndexExpr{Ident: yyDollar[1].Id, Index: yyDollar[3].Expr}
+			yyVAL.Expr = ast.IndexExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Index: yyDollar[3].Expr}
 		}
-	case 84:
+	case 156:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:310
+//line parser.y:633
+		{
+			yyVAL.Expr = ast.SliceExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Low: yyDollar[3].Expr, High: yyDollar[5].Expr}
+		}
+	case 157:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:634
+		{
+			yyVAL.Expr = ast.SliceExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, High: yyDollar[4].Expr}
+		}
+	case 158:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:635
+		{
+			yyVAL.Expr = ast.SliceExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Low: yyDollar[3].Expr}
+		}
+	case 159:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:636
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 85:
+	case 160:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:318
+//line parser.y:645
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "+=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 86:
+	case 161:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:326
+//line parser.y:654
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "-=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 87:
+	case 162:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:334
+//line parser.y:663
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "*=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 88:
+	case 163:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:342
+//line parser.y:672
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "/=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 89:
+	case 164:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:350
+//line parser.y:681
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "%=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 90:
+	case 165:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:358
+//line parser.y:690
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "&=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 91:
+	case 166:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:366
+//line parser.y:699
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "^=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 92:
+	case 167:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:374
+//line parser.y:708
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "|=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 93:
+	case 168:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:382
+//line parser.y:717
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    "<<=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 94:
+	case 169:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:390
+//line parser.y:726
 		{
 			yyVAL.Expr = ast.AssignExprIndexExpr{
-				Ident: yyDollar[1].Id,
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
 				Index: yyDollar[3].Expr,
 				Op:    ">>=",
 				Value: yyDollar[6].Expr,
 			}
 		}
-	case 95:
+	case 170:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:401
+//line parser.y:735
 		{
-			yyVAL.Call = ast.Call{Function: yyDollar[1].Id, Void: true}
+			yyVAL.Expr = ast.FieldExpr{Pos: yyDollar[1].Expr.Position(), Left: yyDollar[1].Expr, Field: yyDollar[3].Id}
 		}
-	case 96:
+	case 171:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:736
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 172:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:745
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "+=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 173:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:754
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "-=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 174:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:763
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "*=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 175:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:772
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "/=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 176:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:781
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "%=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 177:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:790
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "&=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 178:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:799
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "^=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 179:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:808
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "|=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 180:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:817
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    "<<=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 181:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:826
+		{
+			yyVAL.Expr = ast.AssignExprFieldExpr{
+				Pos:   yyDollar[1].Expr.Position(),
+				Left:  yyDollar[1].Expr,
+				Field: yyDollar[3].Id,
+				Op:    ">>=",
+				Value: yyDollar[5].Expr,
+			}
+		}
+	case 182:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:838
+		{
+			yyVAL.Call = ast.Call{Pos: yyDollar[1].Id.Pos, Function: yyDollar[1].Id, Void: true}
+		}
+	case 183:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:404
+//line parser.y:841
 		{
-			yyVAL.Call = ast.Call{Function: yyDollar[1].Id, Arguments: yyDollar[3].ExprList, Void: false}
+			yyVAL.Call = ast.Call{Pos: yyDollar[1].Id.Pos, Function: yyDollar[1].Id, Arguments: yyDollar[3].ExprList, Void: false}
 		}
-	case 97:
+	case 184:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:847
+		{
+			yyVAL.Lambda = ast.Lambda{
+				Pos:        yyDollar[1].Type.Pos,
+				Type:       yyDollar[1].Type,
+				Parameters: make([]ast.Param, 0),
+				Body:       yyDollar[4].Block,
+			}
+		}
+	case 185:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:855
+		{
+			yyVAL.Lambda = ast.Lambda{
+				Pos:        yyDollar[1].Type.Pos,
+				Type:       yyDollar[1].Type,
+				Parameters: yyDollar[3].ParamList,
+				Body:       yyDollar[5].Block,
+			}
+		}
+	case 186:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:410
+//line parser.y:866
 		{
-			yyVAL.Array = ast.Array{Elements: yyDollar[2].ExprList}
+			yyVAL.Array = ast.Array{Pos: yyDollar[1].token.Pos, Elements: yyDollar[2].ExprList}
 		}
-	case 98:
+	case 187:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:867
+		{
+			yyVAL.Array = ast.Array{Pos: yyDollar[1].token.Pos, Elements: make([]ast.Expression, 0)}
+		}
+	case 188:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:414
+//line parser.y:871
 		{
 			yyVAL.ExprList = []ast.Expression{yyDollar[1].Expr}
 		}
-	case 99:
+	case 189:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:415
+//line parser.y:872
 		{
 			yyVAL.ExprList = append(yyDollar[1].ExprList, yyDollar[3].Expr)
 		}
-	case 100:
+	case 190:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:876
+		{
+			yyVAL.Expr = yyDollar[1].Expr
+		}
+	case 191:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:877
+		{
+			yyVAL.Expr = yyDollar[1].Array
+		}
+	case 192:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:881
+		{
+			yyVAL.ExprList = []ast.Expression{yyDollar[1].Expr}
+		}
+	case 193:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:882
+		{
+			yyVAL.ExprList = append(yyDollar[1].ExprList, yyDollar[3].Expr)
+		}
+	case 194:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:419
+//line parser.y:886
 		{
-			yyVAL.Id = ast.Identifier{Name: yyDollar[1].token.Literal}
+			yyVAL.Id = ast.Identifier{Pos: yyDollar[1].token.Pos, Name: yyDollar[1].token.Literal}
 		}
 	}
 	goto yystack /* stack new state and value */
@@ -3,17 +3,16 @@ package parser
 
 import (
     "ariel/ast"
-    "ariel/color"
-    "ariel/misc"
+    "ariel/object"
     "fmt"
     "io"
-    "os"
     "strconv"
     "strings"
 	"text/scanner"
 )
 
 type Token struct {
+    Pos     ast.Pos
 	Literal string
     Int     int64
     Float   float64
@@ -27,58 +26,94 @@ type Token struct {
     DeclList []ast.Statement
     Decl ast.Statement
     Type ast.Type
+    Types []ast.Type
     FuncDecl ast.FuncDecl
     VarDecl ast.VarDecl
+    TupleDecl ast.TupleDecl
+    TargetList []ast.VarDecl
+    StructDecl ast.StructDecl
+    EnumDecl ast.EnumDecl
+    IdList []ast.Identifier
+    FieldList []ast.VarDecl
     ParamList []ast.Param
     Param ast.Param
+    Lambda ast.Lambda
     Block ast.Block
     StmtList []ast.Statement
     Stmt ast.Statement
     While ast.While
+    DoWhile ast.DoWhile
     For ast.For
+    ForEach ast.ForEach
     IfElse ast.IfElse
+    Switch ast.Switch
+    CaseList []ast.Case
+    Case ast.Case
     Return ast.Return
+    Break ast.Break
+    Continue ast.Continue
     ExprStmt ast.ExprStmt
     Expr ast.Expression
     Call ast.Call
     Array ast.Array
     ExprList []ast.Expression
+    Depth int
     Id ast.Identifier
 }
 
-%token<token> CHAR INT FLOAT STRING BOOL VOID
-%token<token> WHILE FOR IF ELSE RETURN
-%token<token> '+' '-' '*' '/' '%' '&' '^' '|' '=' '!' LT LE EQ GE GT AND OR
+%token<token> CHAR INT SIZED BIGINT FLOAT STRING BOOL VOID STRUCT ENUM MAP
+%token<token> CONST WHILE DO FOR IF ELSE RETURN BREAK CONTINUE
+%token<token> SWITCH CASE DEFAULT FALLTHROUGH
+%token<token> '+' '-' '*' '/' '%' '&' '^' '|' '=' '!' '~' LT LE EQ GE GT AND OR
 %token<token> ADD SUB MUL DIV MOD RSHIFT LSHIFT
 %token<token> ADDS SUBS MULS DIVS MODS LSHIFTS RSHIFTS
-%token<token> ID CHARCON INTCON, STRINGCON, FLOATCON, TRUE, FALSE
-%token<token> '(' ')' '{' '}' '[' ']' ';'
+%token<token> INC DEC
+%token<token> ID CHARCON INTCON, BIGINTCON, STRINGCON, FLOATCON, TRUE, FALSE
+%token<token> '(' ')' '{' '}' '[' ']' ';' ':' '.'
 
 %type<Program> Program
 %type<DeclList> DeclList
 %type<Decl> Decl
-%type<Type> Type
+%type<Type> Type MapKey
+%type<Types> TupleTypes
 %type<FuncDecl> FuncDecl
-%type<VarDecl> VarDecl
-%type<ParamList> ParamList
-%type<Param> Param
+%type<VarDecl> VarDecl Target
+%type<TupleDecl> TupleDecl
+%type<TargetList> TargetList
+%type<StructDecl> StructDecl
+%type<EnumDecl> EnumDecl
+%type<IdList> IdList
+%type<FieldList> FieldList
+%type<ParamList> ParamList TypeList
+%type<Param> Param ParamType
+%type<Lambda> Lambda
 %type<Block> Block
 %type<StmtList> StmtList
 %type<Stmt> Stmt
 %type<While> While
+%type<DoWhile> DoWhile
 %type<For> For
+%type<ForEach> ForEach
 %type<IfElse> IfElse
+%type<Switch> Switch
+%type<CaseList> CaseList
+%type<Case> Case CaseBody
 %type<Return> Return
+%type<Break> Break
+%type<Continue> Continue
 %type<ExprStmt> ExprStmt
 %type<Expr> Expr
 %type<Call> Call
 %type<Array> Array
-%type<ExprList> ExprList
+%type<ExprList> ExprList Dims InitList
+%type<Expr> Init
+%type<Depth> EmptyDims
 %type<Id> Id
 
 %nonassoc '{' '}'
 %right IF ELSE
 %right '=' ADDS SUBS MULS DIVS MODS ANDS XORS ORS LSHIFTS RSHIFTS
+%right '?' ':'
 %left OR
 %left AND
 %left '|'
@@ -89,8 +124,8 @@ type Token struct {
 %left LSHIFT RSHIFT
 %left '+' '-'
 %left '*' '/' '%'
-%right NEG POS NOT TILDE
-%left '(' ')' '[' ']'
+%right NEG POS NOT TILDE INC DEC
+%left '(' ')' '[' ']' '.'
 
 %start Program
 
@@ -105,22 +140,58 @@ DeclList
     ;
 
 Decl
-    : Stmt      { $$ = $1 }
-    | FuncDecl  { $$ = $1 }
+    : Stmt          { $$ = $1 }
+    | FuncDecl      { $$ = $1 }
+    | StructDecl    { $$ = $1 }
+    | EnumDecl      { $$ = $1 }
     ;
 
 Type
-    : CHAR    { $$ = ast.Type{Value: $1.Literal} }
-    | INT     { $$ = ast.Type{Value: $1.Literal} }
-    | FLOAT   { $$ = ast.Type{Value: $1.Literal} }
-    | STRING  { $$ = ast.Type{Value: $1.Literal} }
-    | BOOL    { $$ = ast.Type{Value: $1.Literal} }
-    | VOID    { $$ = ast.Type{Value: $1.Literal} }
+    : CHAR    { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | INT     { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | SIZED   { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | BIGINT  { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | FLOAT   { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | STRING  { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | BOOL    { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | VOID    { $$ = ast.Type{Pos: $1.Pos, Value: $1.Literal} }
+    | STRUCT Id             { $$ = ast.Type{Pos: $1.Pos, Value: "struct " + $2.Name} }
+    | ENUM Id               { $$ = ast.Type{Pos: $1.Pos, Value: "enum " + $2.Name} }
+    | MapKey Type '>'       { $$ = ast.Type{Pos: $1.Pos, Value: $1.Value + $2.Value + ">"} }
+    | MapKey MapKey Type RSHIFT {
+        $$ = ast.Type{Pos: $1.Pos, Value: $1.Value + $2.Value + $3.Value + ">>"}
+    }
+    | Type '[' ']'          { $$ = ast.Type{Pos: $1.Pos, Value: $1.Value + "arr"} }
+    | Type '(' ')'          { $$ = ast.FuncType($1, make([]ast.Param, 0)) }
+    | Type '(' TypeList ')' { $$ = ast.FuncType($1, $3) }
+    | '(' Type ',' TupleTypes ')' {
+        $$ = ast.TupleType(append([]ast.Type{$2}, $4...))
+        $$.Pos = $1.Pos
+    }
+    ;
+
+MapKey
+    : MAP '<' Type ',' { $$ = ast.Type{Pos: $1.Pos, Value: "map<" + $3.Value + ","} }
+    ;
+
+TupleTypes
+    : Type                  { $$ = []ast.Type{$1} }
+    | TupleTypes ',' Type   { $$ = append($1, $3) }
+    ;
+
+TypeList
+    : ParamType                 { $$ = []ast.Param{$1} }
+    | TypeList ',' ParamType    { $$ = append($1, $3) }
+    ;
+
+ParamType
+    : Type { $$ = ast.Param{Type: $1, Array: false} }
     ;
 
 FuncDecl
     : Type Id '(' ')' Block {
         $$ = ast.FuncDecl{
+            Pos: $1.Pos,
             Type: $1,
             Ident: $2,
             Parameters: make([]ast.Param, 0),
@@ -129,6 +200,7 @@ FuncDecl
     }
     | Type Id '(' ParamList ')' Block {
         $$ = ast.FuncDecl{
+            Pos: $1.Pos,
             Type: $1,
             Ident: $2,
             Parameters: $4,
@@ -137,9 +209,40 @@ FuncDecl
     }
     ;
 
+StructDecl
+    : STRUCT Id '{' FieldList '}' ';' {
+        $$ = ast.StructDecl{
+            Pos: $1.Pos,
+            Ident: $2,
+            Fields: $4,
+        }
+    }
+    ;
+
+EnumDecl
+    : ENUM Id '{' IdList '}' ';' {
+        $$ = ast.EnumDecl{
+            Pos: $1.Pos,
+            Ident: $2,
+            Members: $4,
+        }
+    }
+    ;
+
+IdList
+    : Id                { $$ = []ast.Identifier{$1} }
+    | IdList ',' Id     { $$ = append($1, $3) }
+    ;
+
+FieldList
+    : VarDecl               { $$ = []ast.VarDecl{$1} }
+    | FieldList VarDecl     { $$ = append($1, $2) }
+    ;
+
 VarDecl
     : Type Id ';' {
         $$ = ast.VarDecl{
+            Pos: $1.Pos,
             Type: $1,
             Ident: $2,
             Initialized: false,
@@ -147,30 +250,78 @@ VarDecl
     }
     | Type Id '=' Expr ';' {
         $$ = ast.VarDecl{
+            Pos: $1.Pos,
             Type: $1,
             Ident: $2,
             Value: $4,
             Initialized: true,
         }
     }
-    | Type Id '[' Expr ']' ';' {
-        $$ = ast.VarDecl {
-            Type: ast.Type{Value: $1.Value + "arr"},
+    | Type Id '=' Array ';' {
+        $$ = ast.VarDecl{
+            Pos: $1.Pos,
+            Type: $1,
             Ident: $2,
             Value: $4,
+            Initialized: true,
+        }
+    }
+    | Type Id Dims ';' {
+        $$ = ast.VarDecl{
+            Pos: $1.Pos,
+            Type: ast.Type{Pos: $1.Pos, Value: $1.Value + strings.Repeat("arr", len($3))},
+            Ident: $2,
+            Dimensions: $3,
             Initialized: false,
         }
     }
-    | Type Id '[' ']' '=' Array ';' {
-        $$ = ast.VarDecl {
-            Type: ast.Type{Value: $1.Value + "arr"},
+    | Type Id EmptyDims '=' Array ';' {
+        $$ = ast.VarDecl{
+            Pos: $1.Pos,
+            Type: ast.Type{Pos: $1.Pos, Value: $1.Value + strings.Repeat("arr", $3)},
             Ident: $2,
-            Value: $6,
+            Value: $5,
             Initialized: true,
         }
     }
     ;
 
+TupleDecl
+    : Type Id ',' TargetList '=' Expr ';' {
+        first := ast.VarDecl{Pos: $1.Pos, Type: $1, Ident: $2}
+        $$ = ast.TupleDecl{
+            Pos: $1.Pos,
+            Decls: append([]ast.VarDecl{first}, $4...),
+            Value: $6,
+        }
+        for i := 1; i < len($$.Decls); i++ {
+            if $$.Decls[i].Type.Value == "" {
+                $$.Decls[i].Type = $$.Decls[i-1].Type
+            }
+        }
+    }
+    ;
+
+TargetList
+    : Target                { $$ = []ast.VarDecl{$1} }
+    | TargetList ',' Target { $$ = append($1, $3) }
+    ;
+
+Target
+    : Id        { $$ = ast.VarDecl{Pos: $1.Pos, Ident: $1} }
+    | Type Id   { $$ = ast.VarDecl{Pos: $1.Pos, Type: $1, Ident: $2} }
+    ;
+
+Dims
+    : '[' Expr ']'          { $$ = []ast.Expression{$2} }
+    | Dims '[' Expr ']'     { $$ = append($1, $3) }
+    ;
+
+EmptyDims
+    : '[' ']'               { $$ = 1 }
+    | EmptyDims '[' ']'     { $$ = $1 + 1 }
+    ;
+
 ParamList
     : Param                 { $$ = []ast.Param{$1} }
     | ParamList ',' Param   { $$ = append($1, $3) }
@@ -178,15 +329,24 @@ ParamList
 
 Param
     : Type Id           { $$ = ast.Param{Type: $1, Ident: $2, Array: false} }
-    | Type Id '[' ']'   { $$ = ast.Param{Type: $1, Ident: $2, Array: true} }
+    | Type Id EmptyDims {
+        $$ = ast.Param{
+            Type: ast.Type{Pos: $1.Pos, Value: $1.Value + strings.Repeat("arr", $3-1)},
+            Ident: $2,
+            Array: true,
+        }
+    }
     ;
 
 Block
     : '{' '}' {
-        $$ = ast.Block{Statements: make([]ast.Statement, 0)}
+        $$ = ast.Block{Pos: $1.Pos, Statements: make([]ast.Statement, 0)}
     }
     | '{' StmtList '}' {
-        $$ = ast.Block{Statements: $2}
+        $$ = ast.Block{Pos: $1.Pos, Statements: $2}
+    }
+    | '{' StmtList error '}' {
+        $$ = ast.Block{Pos: $1.Pos, Statements: $2}
     }
     ;
 
@@ -197,26 +357,77 @@ StmtList
 
 Stmt
     : VarDecl   { $$ = $1 }
+    | TupleDecl { $$ = $1 }
+    | CONST VarDecl {
+        if !$2.Initialized {
+            yylex.Error("const " + $2.Ident.Name + " must be initialized")
+        }
+        $2.Const = true
+        $2.Pos = $1.Pos
+        $$ = $2
+    }
     | Block     { $$ = $1 }
     | While     { $$ = $1 }
+    | DoWhile   { $$ = $1 }
     | For       { $$ = $1 }
+    | ForEach   { $$ = $1 }
     | IfElse    { $$ = $1 }
+    | Switch    { $$ = $1 }
     | Return    { $$ = $1 }
+    | Break     { $$ = $1 }
+    | Continue  { $$ = $1 }
     | ExprStmt  { $$ = $1 }
+    | error ';' {
+        // Stands in for a statement that failed to parse, so that parsing
+        // resumes at the next one and reports any further syntax errors.
+        $$ = ast.Block{Pos: $2.Pos}
+    }
+    | Id ':' While {
+        $3.Pos = $1.Pos
+        $3.Label = $1
+        $$ = $3
+    }
+    | Id ':' DoWhile {
+        $3.Pos = $1.Pos
+        $3.Label = $1
+        $$ = $3
+    }
+    | Id ':' For {
+        $3.Pos = $1.Pos
+        $3.Label = $1
+        $$ = $3
+    }
+    | Id ':' ForEach {
+        $3.Pos = $1.Pos
+        $3.Label = $1
+        $$ = $3
+    }
     ;
 
 While
     : WHILE '(' Expr ')' Stmt {
         $$ = ast.While{
+            Pos: $1.Pos,
             Condition: $3,
             Body: $5,
         }
     }
     ;
 
+DoWhile
+    : DO Stmt WHILE '(' Expr ')' ';' {
+        $$ = ast.DoWhile{
+            Pos: $1.Pos,
+            Body: $2,
+            Condition: $5,
+        }
+    }
+    ;
+
 For
     : FOR '(' Expr ';' Expr ';' Expr ')' Block {
-        $$ = ast.For {
+        $$ = ast.For{
+            Pos: $1.Pos,
             Init: $3,
             Condition: $5,
             Increment: $7,
@@ -224,7 +435,8 @@ For
         }
     }
     | FOR '(' Type Id '=' Expr ';' Expr ';' Expr ')' Block {
-        $$ = ast.For {
+        $$ = ast.For{
+            Pos: $1.Pos,
             VarDecl: true,
             Type: $3,
             Ident: $4,
@@ -236,9 +448,22 @@ For
     }
     ;
 
+ForEach
+    : FOR '(' Type Id ':' Expr ')' Block {
+        $$ = ast.ForEach{
+            Pos: $1.Pos,
+            Type: $3,
+            Ident: $4,
+            Collection: $6,
+            Body: $8,
+        }
+    }
+    ;
+
 IfElse
     : IF '(' Expr ')' Stmt %prec IF {
         $$ = ast.IfElse{
+            Pos: $1.Pos,
             Condition: $3,
             Consequence: $5,
             HasAlternative: false,
@@ -246,6 +471,7 @@ IfElse
     }
     | IF '(' Expr ')' Stmt ELSE Stmt {
         $$ = ast.IfElse{
+            Pos: $1.Pos,
             Condition: $3,
             Consequence: $5,
             Alternative: $7,
@@ -254,160 +480,401 @@ IfElse
     }
     ;
 
+Switch
+    : SWITCH '(' Expr ')' '{' '}' {
+        $$ = ast.Switch{
+            Pos: $1.Pos,
+            Value: $3,
+            Cases: make([]ast.Case, 0),
+        }
+    }
+    | SWITCH '(' Expr ')' '{' CaseList '}' {
+        if last := $6[len($6)-1]; last.Fallthrough {
+            yylex.(*Lexer).errorAt(last.Pos, "cannot fallthrough final case in switch")
+        }
+        $$ = ast.Switch{
+            Pos: $1.Pos,
+            Value: $3,
+            Cases: $6,
+        }
+    }
+    ;
+
+CaseList
+    : Case          { $$ = []ast.Case{$1} }
+    | CaseList Case { $$ = append($1, $2) }
+    ;
+
+Case
+    : CASE ExprList ':' CaseBody {
+        $$ = $4
+        $$.Pos = $1.Pos
+        $$.Body.Pos = $1.Pos
+        $$.Values = $2
+    }
+    | DEFAULT ':' CaseBody {
+        $$ = $3
+        $$.Pos = $1.Pos
+        $$.Body.Pos = $1.Pos
+        $$.Default = true
+    }
+    ;
+
+CaseBody
+    : /* empty */ {
+        $$ = ast.Case{Body: ast.Block{Statements: make([]ast.Statement, 0)}}
+    }
+    | StmtList {
+        $$ = ast.Case{Body: ast.Block{Statements: $1}}
+    }
+    | FALLTHROUGH ';' {
+        $$ = ast.Case{
+            Body: ast.Block{Statements: make([]ast.Statement, 0)},
+            Fallthrough: true,
+        }
+    }
+    | StmtList FALLTHROUGH ';' {
+        $$ = ast.Case{
+            Body: ast.Block{Statements: $1},
+            Fallthrough: true,
+        }
+    }
+    ;
+
 Return
-    : RETURN ';'      { $$ = ast.Return{Void: true} }
-    | RETURN Expr ';' { $$ = ast.Return{Value: $2, Void: false} }
+    : RETURN ';'      { $$ = ast.Return{Pos: $1.Pos, Void: true} }
+    | RETURN ExprList ';' {
+        if len($2) == 1 {
+            $$ = ast.Return{Pos: $1.Pos, Value: $2[0], Void: false}
+        } else {
+            $$ = ast.Return{
+                Pos: $1.Pos,
+                Value: ast.Tuple{Pos: $2[0].Position(), Elements: $2},
+                Void: false,
+            }
+        }
+    }
+    ;
+
+Break
+    : BREAK ';'       { $$ = ast.Break{Pos: $1.Pos} }
+    | BREAK Id ';'    { $$ = ast.Break{Pos: $1.Pos, Label: $2} }
+    ;
+
+Continue
+    : CONTINUE ';'    { $$ = ast.Continue{Pos: $1.Pos} }
+    | CONTINUE Id ';' { $$ = ast.Continue{Pos: $1.Pos, Label: $2} }
     ;
 
 ExprStmt
-    : Expr ';' { $$ = ast.ExprStmt{Expression: $1} }
+    : Expr ';' { $$ = ast.ExprStmt{Pos: $1.Position(), Expression: $1} }
     ;
 
 Expr
-    : Expr '+' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "+", Right: $3} }
-    | Expr '-' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "-", Right: $3} }
-    | Expr '*' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "*", Right: $3} }
-    | Expr '/' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "/", Right: $3} }
-    | Expr '%' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "%", Right: $3} }
-    | Expr '&' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "&", Right: $3} }
-    | Expr '^' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "^", Right: $3} }
-    | Expr '|' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "|", Right: $3} }
-    | Expr LSHIFT Expr         { $$ = ast.InfixExpr{Left: $1, Op: "<<", Right: $3} }
-    | Expr RSHIFT Expr         { $$ = ast.InfixExpr{Left: $1, Op: ">>", Right: $3} }
-    | Expr '<' Expr            { $$ = ast.InfixExpr{Left: $1, Op: "<", Right: $3} }
-    | Expr LE Expr             { $$ = ast.InfixExpr{Left: $1, Op: "<=", Right: $3} }
-    | Expr EQ Expr             { $$ = ast.InfixExpr{Left: $1, Op: "==", Right: $3} }
-    | Expr NE Expr             { $$ = ast.InfixExpr{Left: $1, Op: "!=", Right: $3} }
-    | Expr GE Expr             { $$ = ast.InfixExpr{Left: $1, Op: ">=", Right: $3} }
-    | Expr '>' Expr            { $$ = ast.InfixExpr{Left: $1, Op: ">", Right: $3} }
-    | Expr AND Expr            { $$ = ast.InfixExpr{Left: $1, Op: "&&", Right: $3} }
-    | Expr OR Expr             { $$ = ast.InfixExpr{Left: $1, Op: "||", Right: $3} }
-    | Id '=' Expr              { $$ = ast.Assign{Ident: $1, Value: $3} }
-    | Id ADDS Expr             { $$ = ast.AssignExpr{Ident: $1, Op: "+=", Value: $3} }
-    | Id SUBS Expr             { $$ = ast.AssignExpr{Ident: $1, Op: "-=", Value: $3} }
-    | Id MULS Expr             { $$ = ast.AssignExpr{Ident: $1, Op: "*=", Value: $3} }
-    | Id DIVS Expr             { $$ = ast.AssignExpr{Ident: $1, Op: "/=", Value: $3} }
-    | Id MODS Expr             { $$ = ast.AssignExpr{Ident: $1, Op: "%=", Value: $3} }
-    | Id ANDS Expr             { $$ = ast.AssignExpr{Ident: $1, Op: "&=", Value: $3} }
-    | Id XORS Expr             { $$ = ast.AssignExpr{Ident: $1, Op: "^=", Value: $3} }
-    | Id ORS Expr              { $$ = ast.AssignExpr{Ident: $1, Op: "|=", Value: $3} }
-    | Id LSHIFTS Expr          { $$ = ast.AssignExpr{Ident: $1, Op: "<<=", Value: $3} }
-    | Id RSHIFTS Expr          { $$ = ast.AssignExpr{Ident: $1, Op: ">>=", Value: $3} }
-    | '-' Expr %prec NEG       { $$ = ast.PrefixExpr{Op: "-", Right: $2} }
-    | '+' Expr %prec POS       { $$ = ast.PrefixExpr{Op: "+", Right: $2} }
-    | '!' Expr %prec NOT       { $$ = ast.PrefixExpr{Op: "!", Right: $2} }
-    | '~' Expr %prec TILDE     { $$ = ast.PrefixExpr{Op: "~", Right: $2} }
+    : Expr '+' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "+", Right: $3}) }
+    | Expr '-' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "-", Right: $3}) }
+    | Expr '*' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "*", Right: $3}) }
+    | Expr '/' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "/", Right: $3}) }
+    | Expr '%' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "%", Right: $3}) }
+    | Expr '&' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "&", Right: $3}) }
+    | Expr '^' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "^", Right: $3}) }
+    | Expr '|' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "|", Right: $3}) }
+    | Expr LSHIFT Expr         { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "<<", Right: $3}) }
+    | Expr RSHIFT Expr         { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: ">>", Right: $3}) }
+    | Expr '<' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "<", Right: $3}) }
+    | Expr LE Expr             { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "<=", Right: $3}) }
+    | Expr EQ Expr             { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "==", Right: $3}) }
+    | Expr NE Expr             { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "!=", Right: $3}) }
+    | Expr GE Expr             { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: ">=", Right: $3}) }
+    | Expr '>' Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: ">", Right: $3}) }
+    | Expr AND Expr            { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "&&", Right: $3}) }
+    | Expr OR Expr             { $$ = fold(ast.InfixExpr{Pos: $1.Position(), Left: $1, Op: "||", Right: $3}) }
+    | Expr '?' Expr ':' Expr {
+        $$ = ast.Ternary{
+            Pos: $1.Position(),
+            Condition: $1,
+            Consequence: $3,
+            Alternative: $5,
+        }
+    }
+    | Id '=' Expr              { $$ = ast.Assign{Pos: $1.Pos, Ident: $1, Value: $3} }
+    | Id ADDS Expr             { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "+=", Value: $3} }
+    | Id SUBS Expr             { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "-=", Value: $3} }
+    | Id MULS Expr             { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "*=", Value: $3} }
+    | Id DIVS Expr             { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "/=", Value: $3} }
+    | Id MODS Expr             { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "%=", Value: $3} }
+    | Id ANDS Expr             { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "&=", Value: $3} }
+    | Id XORS Expr             { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "^=", Value: $3} }
+    | Id ORS Expr              { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "|=", Value: $3} }
+    | Id LSHIFTS Expr          { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: "<<=", Value: $3} }
+    | Id RSHIFTS Expr          { $$ = ast.AssignExpr{Pos: $1.Pos, Ident: $1, Op: ">>=", Value: $3} }
+    | Expr INC                 { $$ = incDec(yylex, $1.Position(), $1, "++", false) }
+    | Expr DEC                 { $$ = incDec(yylex, $1.Position(), $1, "--", false) }
+    | INC Expr                 { $$ = incDec(yylex, $1.Pos, $2, "++", true) }
+    | DEC Expr                 { $$ = incDec(yylex, $1.Pos, $2, "--", true) }
+    | '-' Expr %prec NEG       { $$ = fold(ast.PrefixExpr{Pos: $1.Pos, Op: "-", Right: $2}) }
+    | '+' Expr %prec POS       { $$ = ast.PrefixExpr{Pos: $1.Pos, Op: "+", Right: $2} }
+    | '!' Expr %prec NOT       { $$ = fold(ast.PrefixExpr{Pos: $1.Pos, Op: "!", Right: $2}) }
+    | '~' Expr %prec TILDE     { $$ = fold(ast.PrefixExpr{Pos: $1.Pos, Op: "~", Right: $2}) }
     | '(' Expr ')'             { $$ = $2 }
+    | '(' Type ')' Expr %prec NEG { $$ = ast.Cast{Pos: $1.Pos, Type: $2, Value: $4} }
+    | Type '(' Expr ')'        { $$ = ast.Cast{Pos: $1.Pos, Type: $1, Value: $3} }
     | Call                     { $$ = $1 }
+    | Lambda                   { $$ = $1 }
     | Id                       { $$ = $1 }
-    | CHARCON                  { $$ = ast.CharCon{Value: $1.Literal} }
-    | INTCON                   { $$ = ast.IntCon{Value: $1.Int} }
-    | FLOATCON                 { $$ = ast.FloatCon{Value: $1.Float} }
-    | STRINGCON                { $$ = ast.StringCon{Value: $1.Literal} }
-    | TRUE                     { $$ = ast.Bool{Value: $1.Bool} }
-    | FALSE                    { $$ = ast.Bool{Value: $1.Bool} }
-    | Id '[' Expr ']'          { $$ = ast.IndexExpr{Ident: $1, Index: $3} }
-    | Id '[' Expr ']' '=' Expr {
+    | CHARCON                  { $$ = ast.CharCon{Pos: $1.Pos, Value: rune($1.Int)} }
+    | INTCON                   { $$ = ast.IntCon{Pos: $1.Pos, Value: $1.Int} }
+    | BIGINTCON                { $$ = ast.BigIntCon{Pos: $1.Pos, Value: $1.Literal} }
+    | FLOATCON                 { $$ = ast.FloatCon{Pos: $1.Pos, Value: $1.Float} }
+    | STRINGCON                { $$ = ast.StringCon{Pos: $1.Pos, Value: $1.Literal} }
+    | TRUE                     { $$ = ast.Bool{Pos: $1.Pos, Value: $1.Bool} }
+    | FALSE                    { $$ = ast.Bool{Pos: $1.Pos, Value: $1.Bool} }
+    | Expr '[' Expr ']'        { $$ = ast.IndexExpr{Pos: $1.Position(), Left: $1, Index: $3} }
+    | Expr '[' Expr ':' Expr ']' { $$ = ast.SliceExpr{Pos: $1.Position(), Left: $1, Low: $3, High: $5} }
+    | Expr '[' ':' Expr ']'    { $$ = ast.SliceExpr{Pos: $1.Position(), Left: $1, High: $4} }
+    | Expr '[' Expr ':' ']'    { $$ = ast.SliceExpr{Pos: $1.Position(), Left: $1, Low: $3} }
+    | Expr '[' Expr ']' '=' Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' ADDS Expr {
+    | Expr '[' Expr ']' ADDS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "+=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' SUBS Expr {
+    | Expr '[' Expr ']' SUBS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "-=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' MULS Expr {
+    | Expr '[' Expr ']' MULS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "*=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' DIVS Expr {
+    | Expr '[' Expr ']' DIVS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "/=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' MODS Expr {
+    | Expr '[' Expr ']' MODS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "%=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' ANDS Expr {
+    | Expr '[' Expr ']' ANDS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "&=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' XORS Expr {
+    | Expr '[' Expr ']' XORS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "^=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' ORS Expr {
+    | Expr '[' Expr ']' ORS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "|=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' LSHIFTS Expr {
+    | Expr '[' Expr ']' LSHIFTS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: "<<=",
             Value: $6,
         }
     }
-    | Id '[' Expr ']' RSHIFTS Expr {
+    | Expr '[' Expr ']' RSHIFTS Expr {
         $$ = ast.AssignExprIndexExpr{
-            Ident: $1,
+            Pos: $1.Position(),
+            Left: $1,
             Index: $3,
             Op: ">>=",
             Value: $6,
         }
     }
+    | Expr '.' Id              { $$ = ast.FieldExpr{Pos: $1.Position(), Left: $1, Field: $3} }
+    | Expr '.' Id '=' Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id ADDS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "+=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id SUBS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "-=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id MULS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "*=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id DIVS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "/=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id MODS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "%=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id ANDS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "&=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id XORS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "^=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id ORS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "|=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id LSHIFTS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: "<<=",
+            Value: $5,
+        }
+    }
+    | Expr '.' Id RSHIFTS Expr {
+        $$ = ast.AssignExprFieldExpr{
+            Pos: $1.Position(),
+            Left: $1,
+            Field: $3,
+            Op: ">>=",
+            Value: $5,
+        }
+    }
     ;
 
 Call
     : Id '(' ')' {
-        $$ = ast.Call{Function: $1, Void: true}
+        $$ = ast.Call{Pos: $1.Pos, Function: $1, Void: true}
     }
     | Id '(' ExprList ')' {
-        $$ = ast.Call{Function: $1, Arguments: $3, Void: false}
+        $$ = ast.Call{Pos: $1.Pos, Function: $1, Arguments: $3, Void: false}
+    }
+    ;
+
+Lambda
+    : Type '(' ')' Block {
+        $$ = ast.Lambda{
+            Pos: $1.Pos,
+            Type: $1,
+            Parameters: make([]ast.Param, 0),
+            Body: $4,
+        }
+    }
+    | Type '(' ParamList ')' Block {
+        $$ = ast.Lambda{
+            Pos: $1.Pos,
+            Type: $1,
+            Parameters: $3,
+            Body: $5,
+        }
     }
     ;
 
 Array
-    : '{' ExprList '}' { $$ = ast.Array{Elements: $2} }
+    : '{' InitList '}' { $$ = ast.Array{Pos: $1.Pos, Elements: $2} }
+    | '{' '}'          { $$ = ast.Array{Pos: $1.Pos, Elements: make([]ast.Expression, 0)} }
+    ;
+
+InitList
+    : Init                  { $$ = []ast.Expression{$1} }
+    | InitList ',' Init     { $$ = append($1, $3) }
+    ;
+
+Init
+    : Expr  { $$ = $1 }
+    | Array { $$ = $1 }
     ;
 
 ExprList
@@ -416,18 +883,20 @@ ExprList
     ;
 
 Id
-    : ID { $$ = ast.Identifier{Name: $1.Literal} }
+    : ID { $$ = ast.Identifier{Pos: $1.Pos, Name: $1.Literal} }
     ;
 %%
 
 type Lexer struct {
 	scanner.Scanner
 	result ast.Program
+    errors []error
     debug bool
 }
 
 func (l *Lexer) Lex(lval *yySymType) int {
     var ttype int
+    errs := l.ErrorCount
 	token := l.Scan()
     lit := l.TokenText()
     tok := int(token)
@@ -454,6 +923,13 @@ func (l *Lexer) Lex(lval *yySymType) int {
         }
     }
 
+    if lit == "+" || lit == "-" {
+        if l.Peek() == rune(lit[0]) {
+            l.Next()
+            lit += lit
+        }
+    }
+
     if lit == "<" {
         if l.Peek() == '<' {
             l.Next()
@@ -504,49 +980,76 @@ func (l *Lexer) Lex(lval *yySymType) int {
     }
 
     var reserved = map[string]int{
-        "char":   CHAR,
-        "int":    INT,
-        "float":  FLOAT,
-        "string": STRING,
-        "bool":   BOOL,
-        "void":   VOID,
-        "while":  WHILE,
-        "for":    FOR,
-        "if":     IF,
-        "else":   ELSE,
-        "return": RETURN,
-        "true":   TRUE,
-        "false":  FALSE,
-        "<=":     LE,
-        "==":     EQ,
-        "!=":     NE,
-        ">=":     GE,
-        "+=":     ADDS,
-        "-=":     SUBS,
-        "*=":     MULS,
-        "/=":     DIVS,
-        "%=":     MODS,
-        "&=":     ANDS,
-        "^=":     XORS,
-        "|=":     ORS,
-        "&&":     AND,
-        "||":     OR,
-        "<<":     LSHIFT,
-        ">>":     RSHIFT,
-        "<<=":    LSHIFTS,
-        ">>=":    RSHIFTS,
+        "char":        CHAR,
+        "int":         INT,
+        "i8":          SIZED,
+        "i16":         SIZED,
+        "i32":         SIZED,
+        "i64":         SIZED,
+        "u8":          SIZED,
+        "u16":         SIZED,
+        "u32":         SIZED,
+        "u64":         SIZED,
+        "bigint":      BIGINT,
+        "float":       FLOAT,
+        "string":      STRING,
+        "bool":        BOOL,
+        "void":        VOID,
+        "const":       CONST,
+        "struct":      STRUCT,
+        "enum":        ENUM,
+        "map":         MAP,
+        "while":       WHILE,
+        "do":          DO,
+        "for":         FOR,
+        "if":          IF,
+        "else":        ELSE,
+        "return":      RETURN,
+        "break":       BREAK,
+        "continue":    CONTINUE,
+        "switch":      SWITCH,
+        "case":        CASE,
+        "default":     DEFAULT,
+        "fallthrough": FALLTHROUGH,
+        "true":        TRUE,
+        "false":       FALSE,
+        "<=":          LE,
+        "==":          EQ,
+        "!=":          NE,
+        ">=":          GE,
+        "+=":          ADDS,
+        "-=":          SUBS,
+        "*=":          MULS,
+        "/=":          DIVS,
+        "%=":          MODS,
+        "&=":          ANDS,
+        "^=":          XORS,
+        "|=":          ORS,
+        "++":          INC,
+        "--":          DEC,
+        "&&":          AND,
+        "||":          OR,
+        "<<":          LSHIFT,
+        ">>":          RSHIFT,
+        "<<=":         LSHIFTS,
+        ">>=":         RSHIFTS,
     }
 
     if t, ok := reserved[lit]; ok {
         ttype = t
     }
 
-    lval.token = Token{Literal: lit}
+    lval.token = Token{
+        Pos: l.pos(),
+        Literal: lit,
+    }
 
     switch ttype {
     case INTCON:
         if i, err := strconv.ParseInt(lit, 10, 64); err == nil {
             lval.token.Int = i
+        } else {
+            ttype = BIGINTCON
         }
     case FLOATCON:
         if f, err := strconv.ParseFloat(lit, 64); err == nil {
@@ -556,8 +1059,13 @@ func (l *Lexer) Lex(lval *yySymType) int {
         lval.token.Literal = strings.TrimPrefix(lval.token.Literal, "\"")
         lval.token.Literal = strings.TrimSuffix(lval.token.Literal, "\"")
     case CHARCON:
-        lval.token.Literal = strings.TrimPrefix(lval.token.Literal, "'")
-        lval.token.Literal = strings.TrimSuffix(lval.token.Literal, "'")
+        r, _, tail, err := strconv.UnquoteChar(lit[1:], '\'')
+        // A malformed literal is a syntax error, unless the scanner has
+        // already reported it.
+        if (err != nil || tail != "'") && l.ErrorCount == errs {
+            l.Error("invalid char literal " + lit)
+        }
+        lval.token.Int = int64(r)
     case TRUE:
         lval.token.Bool = true
     case FALSE:
@@ -571,28 +1079,220 @@ func (l *Lexer) Lex(lval *yySymType) int {
 	return ttype
 }
 
+func incDec(l yyLexer, pos ast.Pos, target ast.Expression, op string, prefix bool) ast.Expression {
+    switch target := target.(type) {
+    case ast.Identifier:
+        return ast.IncDecExpr{Pos: pos, Ident: target, Op: op, Prefix: prefix}
+    case ast.IndexExpr:
+        return ast.IncDecIndexExpr{
+            Pos: pos,
+            Left: target.Left,
+            Index: target.Index,
+            Op: op,
+            Prefix: prefix,
+        }
+    case ast.FieldExpr:
+        return ast.IncDecFieldExpr{
+            Pos: pos,
+            Left: target.Left,
+            Field: target.Field,
+            Op: op,
+            Prefix: prefix,
+        }
+    default:
+        l.Error("invalid operand for " + op)
+        return target
+    }
+}
+
+// fold evaluates operators applied to literals while parsing, so that an
+// expression such as 60 * 60 * 24 reaches the evaluator as a single literal.
+// Only literal operands are folded: the parser does not track scopes, so a
+// name declared const, as in N * 2, is left to be looked up at runtime.
+// Anything that would be an error at runtime, such as division by zero, is
+// also left for the evaluator to report.
+func fold(e ast.Expression) ast.Expression {
+    pos := e.Position()
+    switch e := e.(type) {
+    case ast.PrefixExpr:
+        switch right := e.Right.(type) {
+        case ast.IntCon:
+            switch e.Op {
+            case "-":
+                return ast.IntCon{Pos: pos, Value: -right.Value}
+            case "~":
+                return ast.IntCon{Pos: pos, Value: ^right.Value}
+            }
+        case ast.FloatCon:
+            if e.Op == "-" {
+                return ast.FloatCon{Pos: pos, Value: -right.Value}
+            }
+        case ast.Bool:
+            if e.Op == "!" {
+                return ast.Bool{Pos: pos, Value: !right.Value}
+            }
+        }
+    case ast.InfixExpr:
+        switch left := e.Left.(type) {
+        case ast.IntCon:
+            if right, ok := e.Right.(ast.IntCon); ok {
+                if folded := foldInt(pos, e.Op, left.Value, right.Value); folded != nil {
+                    return folded
+                }
+            }
+        case ast.FloatCon:
+            if right, ok := e.Right.(ast.FloatCon); ok {
+                if folded := foldFloat(pos, e.Op, left.Value, right.Value); folded != nil {
+                    return folded
+                }
+            }
+        case ast.StringCon:
+            if right, ok := e.Right.(ast.StringCon); ok {
+                switch e.Op {
+                case "+":
+                    return ast.StringCon{Pos: pos, Value: left.Value + right.Value}
+                case "==":
+                    return ast.Bool{Pos: pos, Value: left.Value == right.Value}
+                case "!=":
+                    return ast.Bool{Pos: pos, Value: left.Value != right.Value}
+                }
+            }
+        case ast.Bool:
+            if right, ok := e.Right.(ast.Bool); ok {
+                switch e.Op {
+                case "&&":
+                    return ast.Bool{Pos: pos, Value: left.Value && right.Value}
+                case "||":
+                    return ast.Bool{Pos: pos, Value: left.Value || right.Value}
+                case "==":
+                    return ast.Bool{Pos: pos, Value: left.Value == right.Value}
+                case "!=":
+                    return ast.Bool{Pos: pos, Value: left.Value != right.Value}
+                }
+            }
+        }
+    }
+    return e
+}
+
+func foldInt(pos ast.Pos, op string, l, r int64) ast.Expression {
+    switch op {
+    case "<":
+        return ast.Bool{Pos: pos, Value: l < r}
+    case "<=":
+        return ast.Bool{Pos: pos, Value: l <= r}
+    case "==":
+        return ast.Bool{Pos: pos, Value: l == r}
+    case "!=":
+        return ast.Bool{Pos: pos, Value: l != r}
+    case ">=":
+        return ast.Bool{Pos: pos, Value: l >= r}
+    case ">":
+        return ast.Bool{Pos: pos, Value: l > r}
+    case "+":
+        return ast.IntCon{Pos: pos, Value: l + r}
+    case "-":
+        return ast.IntCon{Pos: pos, Value: l - r}
+    case "*":
+        return ast.IntCon{Pos: pos, Value: l * r}
+    case "/":
+        if r != 0 {
+            return ast.IntCon{Pos: pos, Value: l / r}
+        }
+    case "%":
+        if r != 0 {
+            return ast.IntCon{Pos: pos, Value: l % r}
+        }
+    case "&":
+        return ast.IntCon{Pos: pos, Value: l & r}
+    case "^":
+        return ast.IntCon{Pos: pos, Value: l ^ r}
+    case "|":
+        return ast.IntCon{Pos: pos, Value: l | r}
+    case "<<":
+        if r >= 0 {
+            return ast.IntCon{Pos: pos, Value: l << r}
+        }
+    case ">>":
+        if r >= 0 {
+            return ast.IntCon{Pos: pos, Value: l >> r}
+        }
+    }
+    return nil
+}
+
+func foldFloat(pos ast.Pos, op string, l, r float64) ast.Expression {
+    switch op {
+    case "<":
+        return ast.Bool{Pos: pos, Value: l < r}
+    case "<=":
+        return ast.Bool{Pos: pos, Value: l <= r}
+    case "==":
+        return ast.Bool{Pos: pos, Value: l == r}
+    case "!=":
+        return ast.Bool{Pos: pos, Value: l != r}
+    case ">=":
+        return ast.Bool{Pos: pos, Value: l >= r}
+    case ">":
+        return ast.Bool{Pos: pos, Value: l > r}
+    case "+":
+        return ast.FloatCon{Pos: pos, Value: l + r}
+    case "-":
+        return ast.FloatCon{Pos: pos, Value: l - r}
+    case "*":
+        return ast.FloatCon{Pos: pos, Value: l * r}
+    case "/":
+        if r != 0 {
+            return ast.FloatCon{Pos: pos, Value: l / r}
+        }
+    }
+    return nil
+}
+
+// Error records a syntax error at the current token; parsing carries on
+// from the next statement, so that one run reports as many as it can.
 func (l *Lexer) Error(e string) {
-    err := fmt.Sprintf("%s: line %d, column %d",
-        e, l.Position.Line, l.Position.Column)
-    fmt.Fprintln(os.Stderr, color.Red + misc.Flounder(err) + color.Reset)
+    l.errorAt(l.pos(), e)
 }
 
-func ParseProgram(input io.Reader, debug bool) ast.Program {
-	l := new(Lexer)
-    l.debug = debug
-	l.Init(input)
-    l.Mode = scanner.ScanIdents | scanner.ScanFloats | scanner.ScanChars
-    l.Mode |= scanner.ScanStrings | scanner.SkipComments
+// errorAt records an error found once the tokens at pos have been parsed.
+func (l *Lexer) errorAt(pos ast.Pos, e string) {
+    l.errors = append(l.errors, object.Error{Message: e, Pos: pos})
+}
+
+// pos is where the current token starts.
+func (l *Lexer) pos() ast.Pos {
+    return ast.Pos{
+        File: l.Position.Filename,
+        Line: l.Position.Line,
+        Column: l.Position.Column,
+    }
+}
+
+// ParseProgram parses a program read from input, returning it along with
+// any syntax errors found. The program should not be run if there are any.
+func ParseProgram(input io.Reader, debug bool) (ast.Program, []error) {
+	l := newLexer(input, debug)
+    // Positions name the file being parsed, when there is one.
+    if file, ok := input.(interface{ Name() string }); ok {
+        l.Filename = file.Name()
+    }
+	yyParse(l)
+    return l.result, l.errors
+}
+
+func ParseProgramString(input string, debug bool) (ast.Program, []error) {
+	l := newLexer(strings.NewReader(input), debug)
 	yyParse(l)
-    return l.result
+    return l.result, l.errors
 }
 
-func ParseProgramString(input string, debug bool) ast.Program {
+func newLexer(input io.Reader, debug bool) *Lexer {
 	l := new(Lexer)
     l.debug = debug
-	l.Init(strings.NewReader(input))
+	l.Init(input)
     l.Mode = scanner.ScanIdents | scanner.ScanFloats | scanner.ScanChars
     l.Mode |= scanner.ScanStrings | scanner.SkipComments
-	yyParse(l)
-    return l.result
+    l.Scanner.Error = func(_ *scanner.Scanner, msg string) { l.Error(msg) }
+    return l
 }
@@ -72,7 +72,11 @@ func REPL(debug bool) {
 			}
 			os.Exit(0)
 		default:
-			program := parser.ParseProgramString(line, debug)
+			program, errs := parser.ParseProgramString(line, debug)
+			if len(errs) > 0 {
+				fmt.Println(object.Errors(errs))
+				continue
+			}
 			result := eval.Eval(program, state)
 			if result != nil {
 				fmt.Println(result.Eval())